@@ -0,0 +1,43 @@
+package errors
+
+import "net/http"
+
+// Kinds used by MapAuthErrors and MapOnboardingErrors. They have no
+// message format so the mapped error keeps the original error text as
+// internal message, clients get the public message.
+var (
+	KindTooManyAttempts      = authKind(TextCodeTooManyAttempts).WithPublicMessage("Too many login attempts, try again later")
+	KindTokenExpired         = authKind(TextCodeTokenExpired).WithPublicMessage("The token has expired")
+	KindTokenMalformed       = authKind(TextCodeTokenMalformed).WithPublicMessage("The token is missing or malformed")
+	KindTokenAlreadyUsed     = authKind(TextCodeTokenAlreadyUsed).WithPublicMessage("The token was already used")
+	KindAccountSuspended     = authKind(TextCodeAccountSuspended).WithPublicMessage("The account is suspended")
+	KindAccountDisabled      = authKind(TextCodeAccountDisabled).WithPublicMessage("The account is disabled")
+	KindAccountArchived      = authKind(TextCodeAccountArchived).WithPublicMessage("The account is archived")
+	KindAccountPending       = authKind(TextCodeAccountPending).WithPublicMessage("The account is pending activation")
+	KindAccountLocked        = authKind(TextCodeAccountLocked).WithPublicMessage("The account is locked")
+	KindUnauthorized         = statusKind(http.StatusUnauthorized).WithPublicMessage("Authentication is required")
+	KindForbidden            = statusKind(http.StatusForbidden).WithPublicMessage("You are not allowed to perform this action")
+	KindInviteExpired        = authKind(TextCodeInviteExpired).WithPublicMessage("The invitation has expired")
+	KindInviteUsed           = authKind(TextCodeInviteUsed).WithPublicMessage("The invitation was already used")
+	KindResetNotAllowed      = authKind(TextCodeResetNotAllowed).WithPublicMessage("Password reset is not allowed for this account")
+	KindResetRateLimit       = authKind(TextCodeResetRateLimit).WithPublicMessage("Too many password reset requests, try again later")
+	KindVerificationRequired = authKind(TextCodeVerificationRequired).WithPublicMessage("The email address must be verified")
+	KindVerificationExpired  = authKind(TextCodeVerificationExpired).WithPublicMessage("The verification token has expired")
+	KindFeatureDisabled      = authKind(TextCodeFeatureDisabled).WithPublicMessage("The feature is disabled")
+)
+
+// authKind creates the kind of an auth text code from its entry in
+// authCodeDefinitions, so the category and status are declared once
+func authKind(textCode string) *Kind {
+	for _, def := range authCodeDefinitions {
+		if def.TextCode == textCode {
+			return NewKind(def.Category, def.TextCode).WithCode(def.Status)
+		}
+	}
+	panic("errors: no definition for auth text code " + textCode)
+}
+
+// statusKind creates the kind of the text code derived from an HTTP status
+func statusKind(status int) *Kind {
+	return NewKind(HTTPStatusToCategory(status), HTTPStatusToTextCode(status)).WithCode(status)
+}
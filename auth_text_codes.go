@@ -1,5 +1,7 @@
 package errors
 
+import "net/http"
+
 const (
 	TextCodeInvalidCredentials   = "INVALID_CREDENTIALS"
 	TextCodeTooManyAttempts      = "TOO_MANY_ATTEMPTS"
@@ -25,3 +27,53 @@ const (
 	TextCodeInviteUsed           = "INVITE_USED"
 	TextCodeFeatureDisabled      = "FEATURE_DISABLED"
 )
+
+// authCodeDefinitions registers the auth and onboarding text codes in the DefaultCatalog
+var authCodeDefinitions = []CodeDefinition{
+	{TextCode: TextCodeInvalidCredentials, Category: CategoryAuth, Status: http.StatusUnauthorized,
+		Description: "The supplied credentials are invalid", Remediation: "Check the identifier and password and try again"},
+	{TextCode: TextCodeTooManyAttempts, Category: CategoryRateLimit, Status: http.StatusTooManyRequests,
+		Description: "Too many login attempts", Remediation: "Wait before trying to log in again"},
+	{TextCode: TextCodeSessionNotFound, Category: CategoryAuth, Status: http.StatusUnauthorized,
+		Description: "The session does not exist or has ended", Remediation: "Log in again"},
+	{TextCode: TextCodeSessionDecodeError, Category: CategoryAuth, Status: http.StatusUnauthorized,
+		Description: "The session could not be decoded", Remediation: "Log in again"},
+	{TextCode: TextCodeClaimsMappingError, Category: CategoryAuth, Status: http.StatusUnauthorized,
+		Description: "The token claims could not be mapped to a user", Remediation: "Log in again"},
+	{TextCode: TextCodeDataParseError, Category: CategoryBadInput, Status: http.StatusBadRequest,
+		Description: "The request payload could not be parsed", Remediation: "Check the request body format"},
+	{TextCode: TextCodeEmptyPassword, Category: CategoryValidation, Status: http.StatusBadRequest,
+		Description: "An empty password is not allowed", Remediation: "Provide a password"},
+	{TextCode: TextCodeTokenExpired, Category: CategoryAuth, Status: http.StatusUnauthorized,
+		Description: "The token has expired", Remediation: "Refresh the token or log in again"},
+	{TextCode: TextCodeTokenMalformed, Category: CategoryAuth, Status: http.StatusBadRequest,
+		Description: "The token is missing or malformed", Remediation: "Send a valid bearer token"},
+	{TextCode: TextCodeTokenAlreadyUsed, Category: CategoryConflict, Status: http.StatusConflict,
+		Description: "The single use token was already used", Remediation: "Request a new token"},
+	{TextCode: TextCodeImmutableClaim, Category: CategoryAuthz, Status: http.StatusForbidden,
+		Description: "An immutable claim cannot be changed"},
+	{TextCode: TextCodeAccountSuspended, Category: CategoryAuth, Status: http.StatusForbidden,
+		Description: "The account is suspended", Remediation: "Contact support"},
+	{TextCode: TextCodeAccountDisabled, Category: CategoryAuth, Status: http.StatusForbidden,
+		Description: "The account is disabled", Remediation: "Contact support"},
+	{TextCode: TextCodeAccountArchived, Category: CategoryAuth, Status: http.StatusForbidden,
+		Description: "The account is archived", Remediation: "Contact support"},
+	{TextCode: TextCodeAccountPending, Category: CategoryAuth, Status: http.StatusForbidden,
+		Description: "The account is pending activation", Remediation: "Complete the activation steps"},
+	{TextCode: TextCodeAccountLocked, Category: CategoryAuth, Status: http.StatusForbidden,
+		Description: "The account is locked", Remediation: "Wait for the lockout to expire or reset the password"},
+	{TextCode: TextCodeResetRateLimit, Category: CategoryRateLimit, Status: http.StatusTooManyRequests,
+		Description: "Too many password reset requests", Remediation: "Wait before requesting another reset"},
+	{TextCode: TextCodeResetNotAllowed, Category: CategoryAuthz, Status: http.StatusForbidden,
+		Description: "Password reset is not allowed for this account"},
+	{TextCode: TextCodeVerificationRequired, Category: CategoryAuth, Status: http.StatusForbidden,
+		Description: "The email address must be verified", Remediation: "Follow the link in the verification email"},
+	{TextCode: TextCodeVerificationExpired, Category: CategoryAuth, Status: http.StatusForbidden,
+		Description: "The verification token has expired", Remediation: "Request a new verification email"},
+	{TextCode: TextCodeInviteExpired, Category: CategoryBadInput, Status: http.StatusGone,
+		Description: "The invitation has expired", Remediation: "Ask for a new invitation"},
+	{TextCode: TextCodeInviteUsed, Category: CategoryConflict, Status: http.StatusConflict,
+		Description: "The invitation was already used"},
+	{TextCode: TextCodeFeatureDisabled, Category: CategoryAuthz, Status: http.StatusForbidden,
+		Description: "The feature is disabled"},
+}
@@ -0,0 +1,200 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CodeDefinition documents a text code registered in a Catalog
+type CodeDefinition struct {
+	TextCode    string   `json:"text_code"`
+	Category    Category `json:"category"`
+	Status      int      `json:"status"`
+	Description string   `json:"description,omitempty"`
+	Remediation string   `json:"remediation,omitempty"`
+	DocsURL     string   `json:"docs_url,omitempty"`
+}
+
+// Catalog is a thread-safe registry of text codes. Each text code can
+// only be registered once
+type Catalog struct {
+	mu    sync.RWMutex
+	codes map[string]CodeDefinition
+}
+
+// DefaultCatalog holds the text codes used by this package and any
+// code registered through RegisterCode or MustRegisterCode
+var DefaultCatalog = newDefaultCatalog()
+
+// NewCatalog creates an empty Catalog
+func NewCatalog() *Catalog {
+	return &Catalog{
+		codes: make(map[string]CodeDefinition),
+	}
+}
+
+// Register adds a definition to the catalog. It returns an error if the
+// text code is empty or already registered
+func (c *Catalog) Register(def CodeDefinition) error {
+	if def.TextCode == "" {
+		return fmt.Errorf("catalog: text code is required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.codes[def.TextCode]; ok {
+		return fmt.Errorf("catalog: duplicate text code %s (registered as %s/%d)",
+			def.TextCode, existing.Category, existing.Status)
+	}
+
+	c.codes[def.TextCode] = def
+	return nil
+}
+
+// MustRegister adds all definitions to the catalog and panics on the
+// first invalid or duplicate one. Meant to be called from init
+func (c *Catalog) MustRegister(defs ...CodeDefinition) {
+	for _, def := range defs {
+		if err := c.Register(def); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Lookup returns the definition registered for code
+func (c *Catalog) Lookup(code string) (CodeDefinition, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	def, ok := c.codes[code]
+	return def, ok
+}
+
+// IsKnown returns true if code is registered
+func (c *Catalog) IsKnown(code string) bool {
+	_, ok := c.Lookup(code)
+	return ok
+}
+
+// Definitions returns all registered definitions sorted by text code
+func (c *Catalog) Definitions() []CodeDefinition {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	defs := make([]CodeDefinition, 0, len(c.codes))
+	for _, def := range c.codes {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].TextCode < defs[j].TextCode
+	})
+	return defs
+}
+
+// Len returns the number of registered text codes
+func (c *Catalog) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.codes)
+}
+
+// UnknownTextCodes returns the text codes found in the tree of err that
+// are not registered in the catalog
+func (c *Catalog) UnknownTextCodes(err error) []string {
+	var unknown []string
+	seen := make(map[string]bool)
+	Walk(err, func(node error, _ int) bool {
+		e := richErrorOf(node)
+		if e == nil || e.TextCode == "" || seen[e.TextCode] {
+			return true
+		}
+		seen[e.TextCode] = true
+		if !c.IsKnown(e.TextCode) {
+			unknown = append(unknown, e.TextCode)
+		}
+		return true
+	})
+	return unknown
+}
+
+// RegisterCode adds a definition to the DefaultCatalog
+func RegisterCode(def CodeDefinition) error {
+	return DefaultCatalog.Register(def)
+}
+
+// MustRegisterCode adds definitions to the DefaultCatalog, panics on duplicates
+func MustRegisterCode(defs ...CodeDefinition) {
+	DefaultCatalog.MustRegister(defs...)
+}
+
+// LookupCode returns the definition registered in the DefaultCatalog for code
+func LookupCode(code string) (CodeDefinition, bool) {
+	return DefaultCatalog.Lookup(code)
+}
+
+// Text codes produced by this package outside of the auth flows
+const (
+	TextCodeInternalError        = "INTERNAL_ERROR"
+	TextCodeExternalServiceError = "EXTERNAL_SERVICE_ERROR"
+)
+
+func newDefaultCatalog() *Catalog {
+	c := NewCatalog()
+
+	c.MustRegister(
+		CodeDefinition{
+			TextCode:    TextCodeInternalError,
+			Category:    CategoryInternal,
+			Status:      http.StatusInternalServerError,
+			Description: "An unexpected error occurred",
+			Remediation: "Retry later or contact support with the request ID",
+		},
+		CodeDefinition{
+			TextCode:    TextCodeExternalServiceError,
+			Category:    CategoryExternal,
+			Status:      http.StatusBadGateway,
+			Description: "An upstream service failed",
+			Remediation: "Retry the request after a short delay",
+		},
+	)
+
+	// Text codes derived from HTTP status codes by HTTPStatusToTextCode
+	for code := 400; code < 600; code++ {
+		if http.StatusText(code) == "" {
+			continue
+		}
+		c.MustRegister(CodeDefinition{
+			TextCode:    HTTPStatusToTextCode(code),
+			Category:    HTTPStatusToCategory(code),
+			Status:      code,
+			Description: http.StatusText(code),
+		})
+	}
+
+	c.MustRegister(authCodeDefinitions...)
+
+	// Text codes of gRPC codes set by MapGRPCErrors, names shared with an
+	// HTTP status (NOT_FOUND) keep the HTTP definition
+	for code := GRPCCodeCanceled; code <= GRPCCodeUnauthenticated; code++ {
+		if c.IsKnown(code.TextCode()) {
+			continue
+		}
+		c.MustRegister(CodeDefinition{
+			TextCode:    code.TextCode(),
+			Category:    grpcCodeCategories[code],
+			Status:      code.HTTPStatus(),
+			Description: grpcCodeDescription(code),
+		})
+	}
+
+	return c
+}
+
+// grpcCodeDescription turns the code name into a sentence, e.g. Deadline exceeded
+func grpcCodeDescription(code GRPCCode) string {
+	text := strings.ReplaceAll(strings.ToLower(code.TextCode()), "_", " ")
+	return strings.ToUpper(text[:1]) + text[1:]
+}
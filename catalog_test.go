@@ -0,0 +1,126 @@
+package errors_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/go-errors"
+)
+
+func TestCatalog_Register(t *testing.T) {
+	catalog := errors.NewCatalog()
+
+	def := errors.CodeDefinition{
+		TextCode:    "USER_NOT_FOUND",
+		Category:    errors.CategoryNotFound,
+		Status:      http.StatusNotFound,
+		Description: "The user does not exist",
+		Remediation: "Check the user ID",
+		DocsURL:     "https://docs.example.com/errors/USER_NOT_FOUND",
+	}
+
+	if err := catalog.Register(def); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, ok := catalog.Lookup("USER_NOT_FOUND")
+	if !ok || got != def {
+		t.Errorf("Expected definition to be registered, got %+v (found=%t)", got, ok)
+	}
+
+	duplicate := def
+	duplicate.Category = errors.CategoryValidation
+	err := catalog.Register(duplicate)
+	if err == nil || !strings.Contains(err.Error(), "duplicate text code USER_NOT_FOUND") {
+		t.Errorf("Expected duplicate error, got %v", err)
+	}
+
+	if err := catalog.Register(errors.CodeDefinition{}); err == nil {
+		t.Error("Expected error for empty text code")
+	}
+
+	if catalog.Len() != 1 {
+		t.Errorf("Expected 1 definition, got %d", catalog.Len())
+	}
+}
+
+func TestCatalog_MustRegisterPanicsOnDuplicate(t *testing.T) {
+	catalog := errors.NewCatalog()
+	catalog.MustRegister(errors.CodeDefinition{TextCode: "A"})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustRegister to panic on duplicate")
+		}
+	}()
+	catalog.MustRegister(errors.CodeDefinition{TextCode: "A"})
+}
+
+func TestCatalog_Definitions(t *testing.T) {
+	catalog := errors.NewCatalog()
+	catalog.MustRegister(
+		errors.CodeDefinition{TextCode: "B"},
+		errors.CodeDefinition{TextCode: "A"},
+		errors.CodeDefinition{TextCode: "C"},
+	)
+
+	defs := catalog.Definitions()
+	if len(defs) != 3 || defs[0].TextCode != "A" || defs[1].TextCode != "B" || defs[2].TextCode != "C" {
+		t.Errorf("Expected definitions sorted by text code, got %v", defs)
+	}
+}
+
+func TestCatalog_UnknownTextCodes(t *testing.T) {
+	catalog := errors.NewCatalog()
+	catalog.MustRegister(errors.CodeDefinition{TextCode: "KNOWN"})
+
+	err := errors.WrapAll(errors.CategoryOperation, "batch",
+		errors.New("a").WithTextCode("KNOWN"),
+		fmt.Errorf("wrapped: %w", errors.New("b").WithTextCode("MYSTERY")),
+		errors.New("c").WithTextCode("MYSTERY"),
+	)
+
+	unknown := catalog.UnknownTextCodes(err)
+	if len(unknown) != 1 || unknown[0] != "MYSTERY" {
+		t.Errorf("Expected [MYSTERY], got %v", unknown)
+	}
+}
+
+func TestDefaultCatalog_BuiltinCodes(t *testing.T) {
+	codes := []string{
+		errors.TextCodeInvalidCredentials,
+		errors.TextCodeFeatureDisabled,
+		errors.TextCodeInternalError,
+		errors.TextCodeExternalServiceError,
+		errors.HTTPStatusToTextCode(http.StatusNotFound),
+		errors.HTTPStatusToTextCode(http.StatusTooManyRequests),
+	}
+	for _, code := range codes {
+		if _, ok := errors.LookupCode(code); !ok {
+			t.Errorf("Expected %s to be registered in the default catalog", code)
+		}
+	}
+
+	kinds := []*errors.Kind{
+		errors.KindTooManyAttempts, errors.KindTokenExpired, errors.KindTokenMalformed,
+		errors.KindUnauthorized, errors.KindForbidden, errors.KindInviteExpired,
+		errors.KindFeatureDisabled, errors.KindAccountLocked,
+	}
+	for _, kind := range kinds {
+		def, ok := errors.LookupCode(kind.TextCode)
+		if !ok {
+			t.Errorf("Expected kind %s to be registered", kind)
+			continue
+		}
+		if def.Category != kind.Category || def.Status != kind.Code {
+			t.Errorf("Kind %s disagrees with catalog: %+v", kind, def)
+		}
+	}
+
+	mapped := errors.MapToError(fmt.Errorf("boom"), nil)
+	if unknown := errors.DefaultCatalog.UnknownTextCodes(mapped); len(unknown) != 0 {
+		t.Errorf("Expected MapToError text codes to be registered, got %v", unknown)
+	}
+}
@@ -1,14 +1,47 @@
 package errors
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 // Category represents a high level error category
 type Category string
 
 func (c Category) String() string { return string(c) }
 
+// Extend creates a sub-category of c, CategoryValidation.Extend("email")
+// is validation_email and IsA(CategoryValidation)
 func (c Category) Extend(s string) Category { return Category(string(c) + "_" + strings.ToLower(s)) }
 
+// Parent returns the parent category resolved by the
+// DefaultCategoryRegistry, empty for top level categories
+func (c Category) Parent() Category { return DefaultCategoryRegistry.Parent(c) }
+
+// Ancestors returns the parents of c from the closest to the top level one
+func (c Category) Ancestors() []Category {
+	var ancestors []Category
+	seen := map[Category]bool{c: true}
+	for parent := c.Parent(); parent != "" && !seen[parent]; parent = parent.Parent() {
+		seen[parent] = true
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// IsA reports whether c is category or one of its descendants
+func (c Category) IsA(category Category) bool {
+	if c == category {
+		return true
+	}
+	for _, ancestor := range c.Ancestors() {
+		if ancestor == category {
+			return true
+		}
+	}
+	return false
+}
+
 const (
 	CategoryValidation       Category = "validation"
 	CategoryAuth             Category = "authentication"
@@ -27,52 +60,227 @@ const (
 	CategoryCommand          Category = "command"
 )
 
-// TODO: Should this be how IsCategory actually functions?!
-func HasCategory(err error, category Category) bool {
-	if IsCategory(err, category) {
-		return true
+// DefaultPublicMessage is shown to clients for categories without a
+// generic public message
+const DefaultPublicMessage = "An unexpected error occurred"
+
+// CategoryPublicMessage returns the generic client facing message of
+// the category, DefaultPublicMessage when it declares none
+func CategoryPublicMessage(category Category) string {
+	if message := DefaultCategoryRegistry.Definition(category).PublicMessage; message != "" {
+		return message
 	}
+	return DefaultPublicMessage
+}
 
-	if unwrapped := Unwrap(err); unwrapped != nil {
-		return HasCategory(unwrapped, category)
+// SetCategoryPublicMessage sets the generic client facing message of the
+// category. Meant to be called during startup
+func SetCategoryPublicMessage(category Category, message string) {
+	DefaultCategoryRegistry.Update(category, func(def *CategoryDefinition) {
+		def.PublicMessage = message
+	})
+}
+
+// CategoryHTTPStatus returns the HTTP status of the category, 500 for
+// unknown categories
+func CategoryHTTPStatus(category Category) int {
+	return DefaultCategoryRegistry.Definition(category).HTTPStatus
+}
+
+// SetCategoryHTTPStatus sets the HTTP status of the category. Meant to
+// be called during startup
+func SetCategoryHTTPStatus(category Category, status int) {
+	DefaultCategoryRegistry.Update(category, func(def *CategoryDefinition) {
+		def.HTTPStatus = status
+	})
+}
+
+// Category sentinels, errors.Is(err, ErrNotFound) matches any
+// error in the chain with CategoryNotFound or one of its sub-categories
+var (
+	ErrValidation       = newSentinel("validation failed", CategoryValidation)
+	ErrAuth             = newSentinel("authentication failed", CategoryAuth)
+	ErrAuthz            = newSentinel("authorization failed", CategoryAuthz)
+	ErrOperation        = newSentinel("operation failed", CategoryOperation)
+	ErrNotFound         = newSentinel("not found", CategoryNotFound)
+	ErrConflict         = newSentinel("conflict", CategoryConflict)
+	ErrRateLimit        = newSentinel("rate limit exceeded", CategoryRateLimit)
+	ErrBadInput         = newSentinel("bad input", CategoryBadInput)
+	ErrInternal         = newSentinel("internal error", CategoryInternal)
+	ErrExternal         = newSentinel("external service error", CategoryExternal)
+	ErrMiddleware       = newSentinel("middleware error", CategoryMiddleware)
+	ErrRouting          = newSentinel("routing error", CategoryRouting)
+	ErrHandler          = newSentinel("handler error", CategoryHandler)
+	ErrMethodNotAllowed = newSentinel("method not allowed", CategoryMethodNotAllowed)
+	ErrCommand          = newSentinel("command failed", CategoryCommand)
+)
+
+// newSentinel creates a frozen category sentinel without location or
+// timestamp, fluent calls on it return a clone
+func newSentinel(message string, category Category) *Error {
+	sentinel := &Error{
+		Category: category,
+		Message:  message,
+		Severity: categorySeverity(category),
 	}
-	return false
+	return sentinel.Freeze()
+}
+
+// CategoriesOf returns every category found in the tree of err, including
+// every branch of multi cause errors, from the outermost one and without
+// duplicates
+func CategoriesOf(err error) []Category {
+	var categories []Category
+	seen := make(map[Category]bool)
+	Walk(err, func(node error, _ int) bool {
+		if e := richErrorOf(node); e != nil && e.Category != "" && !seen[e.Category] {
+			seen[e.Category] = true
+			categories = append(categories, e.Category)
+		}
+		return true
+	})
+	return categories
+}
+
+// OutermostCategory returns the category of the first rich error in the
+// tree of err, empty when there is none
+func OutermostCategory(err error) Category {
+	if e := richErrorOf(Find(err, isRichError)); e != nil {
+		return e.Category
+	}
+	return ""
+}
+
+// InnermostCategory returns the category of the deepest rich error on
+// the chain of err, empty when there is none. When an error has several
+// causes the first branch is followed, as in RootCause
+func InnermostCategory(err error) Category {
+	var category Category
+	Walk(err, func(node error, _ int) bool {
+		if e := richErrorOf(node); e != nil {
+			category = e.Category
+		}
+		// the first leaf ends the first branch
+		return len(unwrapAll(node)) > 0
+	})
+	return category
+}
+
+// HasAnyCategory reports whether any error in the tree of err has one of
+// the given categories
+func HasAnyCategory(err error, categories ...Category) bool {
+	return Find(err, func(node error) bool {
+		e := richErrorOf(node)
+		return e != nil && slices.Contains(categories, e.Category)
+	}) != nil
+}
+
+// HasCategory reports whether any error in the tree of err, including
+// every branch of multi cause errors, has the given category
+func HasCategory(err error, category Category) bool {
+	return HasAnyCategory(err, category)
 }
 
+// HasCategoryOrDescendant is like HasCategory but also matches
+// sub-categories of category
+func HasCategoryOrDescendant(err error, category Category) bool {
+	return Find(err, func(node error) bool {
+		e := richErrorOf(node)
+		return e != nil && e.Category.IsA(category)
+	}) != nil
+}
+
+// IsCategory reports whether the first rich error found by As in the
+// chain of err has exactly the given category, use HasCategory to search
+// the whole tree and IsCategoryOrDescendant to include sub-categories
 func IsCategory(err error, category Category) bool {
+	cat, ok := firstCategory(err)
+	return ok && cat == category
+}
+
+// IsCategoryOrDescendant reports whether the first rich error in the
+// chain of err has the given category or one of its sub-categories
+func IsCategoryOrDescendant(err error, category Category) bool {
+	cat, ok := firstCategory(err)
+	return ok && cat.IsA(category)
+}
+
+func firstCategory(err error) (Category, bool) {
 	if err == nil {
-		return false
+		return "", false
 	}
 
 	var e *Error
 	if As(err, &e) {
-		return e.Category == category
+		return e.Category, true
 	}
 
 	var retryableErr *RetryableError
 	if As(err, &retryableErr) && retryableErr.BaseError != nil {
-		return retryableErr.BaseError.Category == category
+		return retryableErr.BaseError.Category, true
 	}
 
-	return false
+	return "", false
+}
+
+// RootCategoryMode selects how RootCategory resolves a category
+type RootCategoryMode int
+
+const (
+	// RootCategoryLegacy uses the category of the root cause, which is
+	// CategoryInternal when the root cause is a foreign error
+	RootCategoryLegacy RootCategoryMode = iota
+	// RootCategoryInnermost uses the innermost category of the chain
+	RootCategoryInnermost
+)
+
+var rootCategoryMode = RootCategoryLegacy
+
+// SetRootCategoryMode sets how RootCategory resolves a category, the
+// default RootCategoryLegacy keeps the behavior of earlier releases.
+// Meant to be called during startup
+func SetRootCategoryMode(mode RootCategoryMode) {
+	rootCategoryMode = mode
+}
+
+// GetRootCategoryMode returns the mode used by RootCategory
+func GetRootCategoryMode() RootCategoryMode {
+	return rootCategoryMode
+}
+
+// RootCategory returns the category of the root cause of err, or its
+// innermost category with RootCategoryInnermost. It falls back to
+// CategoryInternal, see SetRootCategoryMode
+func RootCategory(err error) Category {
+	if rootCategoryMode == RootCategoryInnermost {
+		if category := InnermostCategory(err); category != "" {
+			return category
+		}
+		return CategoryInternal
+	}
+
+	if rootErr := richErrorOf(RootCause(err)); rootErr != nil {
+		return rootErr.Category
+	}
+	return CategoryInternal
 }
 
 func IsValidation(err error) bool {
-	return IsCategory(err, CategoryValidation)
+	return IsCategoryOrDescendant(err, CategoryValidation)
 }
 
 func IsAuth(err error) bool {
-	return IsCategory(err, CategoryAuth)
+	return IsCategoryOrDescendant(err, CategoryAuth)
 }
 
 func IsNotFound(err error) bool {
-	return IsCategory(err, CategoryNotFound)
+	return IsCategoryOrDescendant(err, CategoryNotFound)
 }
 
 func IsInternal(err error) bool {
-	return IsCategory(err, CategoryInternal)
+	return IsCategoryOrDescendant(err, CategoryInternal)
 }
 
 func IsCommand(err error) bool {
-	return IsCategory(err, CategoryCommand)
+	return IsCategoryOrDescendant(err, CategoryCommand)
 }
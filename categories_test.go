@@ -81,3 +81,155 @@ func TestCategoryCheckers(t *testing.T) {
 		})
 	}
 }
+
+func TestCategoryHierarchy(t *testing.T) {
+	email := errors.CategoryValidation.Extend("email")
+	format := email.Extend("format")
+
+	tests := []struct {
+		category errors.Category
+		parent   errors.Category
+	}{
+		{email, errors.CategoryValidation},
+		{format, errors.CategoryValidation},
+		{errors.CategoryValidation, ""},
+		{errors.CategoryNotFound, ""},
+		{errors.CategoryMethodNotAllowed, ""},
+		{errors.CategoryNotFound.Extend("user"), errors.CategoryNotFound},
+	}
+	for _, tt := range tests {
+		if got := tt.category.Parent(); got != tt.parent {
+			t.Errorf("Expected parent of %s to be %q, got %q", tt.category, tt.parent, got)
+		}
+	}
+
+	if !format.IsA(errors.CategoryValidation) || !email.IsA(email) {
+		t.Error("Expected sub-categories to be their ancestors")
+	}
+	if errors.CategoryValidation.IsA(email) || email.IsA(errors.CategoryAuth) {
+		t.Error("Expected IsA to only match ancestors")
+	}
+}
+
+func TestCategoryHierarchyRegisteredParent(t *testing.T) {
+	registry := errors.NewCategoryRegistry()
+	registry.MustRegister(
+		errors.NewCategoryDefinition(errors.CategoryValidation).WithHTTPStatus(400),
+		errors.NewCategoryDefinition("validation_email").WithHTTPStatus(422),
+		errors.NewCategoryDefinition("captcha").WithParent(errors.CategoryValidation),
+	)
+
+	if got := registry.Parent("validation_email_format"); got != "validation_email" {
+		t.Errorf("Expected longest registered prefix, got %q", got)
+	}
+	if got := registry.Parent("captcha"); got != errors.CategoryValidation {
+		t.Errorf("Expected explicit parent, got %q", got)
+	}
+
+	def := registry.Definition("validation_email_format")
+	if def.HTTPStatus != 422 || def.Parent != "validation_email" {
+		t.Errorf("Expected definition inherited from validation_email, got %d/%q", def.HTTPStatus, def.Parent)
+	}
+
+	if err := registry.Register(errors.NewCategoryDefinition("loop").WithParent("loop")); err == nil {
+		t.Error("Expected a category to not be its own parent")
+	}
+}
+
+func TestSubCategoryMatching(t *testing.T) {
+	email := errors.CategoryValidation.Extend("email")
+	err := errors.Wrap(errors.New("invalid email", email), errors.CategoryInternal, "signup")
+
+	if errors.IsCategory(err, errors.CategoryValidation) {
+		t.Error("Expected IsCategory to match the exact category only")
+	}
+	if !errors.IsCategoryOrDescendant(err, errors.CategoryValidation) {
+		t.Error("Expected IsCategoryOrDescendant to match the parent category")
+	}
+	if !errors.IsValidation(err) {
+		t.Error("Expected IsValidation to match a validation sub-category")
+	}
+	if !errors.Is(err, errors.ErrValidation) {
+		t.Error("Expected the validation sentinel to match a sub-category")
+	}
+	if errors.Is(errors.New("invalid", errors.CategoryValidation), errors.New("", email)) {
+		t.Error("Expected a parent category not to match a sub-category target")
+	}
+
+	joined := errors.WrapAll(errors.CategoryOperation, "outer", errors.New("a", errors.CategoryAuth), errors.New("b", email))
+	if !errors.HasCategoryOrDescendant(joined, errors.CategoryValidation) {
+		t.Error("Expected HasCategoryOrDescendant to find the sub-category in a branch")
+	}
+	if errors.HasCategory(joined, errors.CategoryValidation) {
+		t.Error("Expected HasCategory to match the exact category only")
+	}
+
+	if got := errors.New("x", email).HTTPStatus(); got != 400 {
+		t.Errorf("Expected sub-category to inherit status 400, got %d", got)
+	}
+}
+
+func TestCategoryQueries(t *testing.T) {
+	notFound := errors.New("user not found", errors.CategoryNotFound)
+	retryable := errors.NewRetryable("db timeout", errors.CategoryExternal)
+	wrapped := &errors.Error{
+		Category: errors.CategoryOperation,
+		Message:  "load profile",
+		Source:   fmt.Errorf("query: %w", notFound),
+	}
+	multi := errors.WrapAll(errors.CategoryHandler, "request failed", wrapped, retryable, fmt.Errorf("plain"))
+
+	got := errors.CategoriesOf(multi)
+	want := []errors.Category{errors.CategoryHandler, errors.CategoryOperation, errors.CategoryNotFound, errors.CategoryExternal}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected categories %v, got %v", want, got)
+	}
+
+	if got := errors.OutermostCategory(multi); got != errors.CategoryHandler {
+		t.Errorf("Expected outermost handler, got %s", got)
+	}
+	if got := errors.InnermostCategory(multi); got != errors.CategoryNotFound {
+		t.Errorf("Expected innermost not_found, got %s", got)
+	}
+	if got := errors.OutermostCategory(fmt.Errorf("plain")); got != "" {
+		t.Errorf("Expected no category for a plain error, got %s", got)
+	}
+
+	if !errors.HasAnyCategory(multi, errors.CategoryConflict, errors.CategoryExternal) {
+		t.Error("Expected HasAnyCategory to find the retryable branch")
+	}
+	if errors.HasAnyCategory(multi, errors.CategoryConflict, errors.CategoryAuth) {
+		t.Error("Expected HasAnyCategory to return false for missing categories")
+	}
+	if errors.HasAnyCategory(multi) {
+		t.Error("Expected HasAnyCategory without categories to return false")
+	}
+
+	if !errors.IsCategory(retryable, errors.CategoryExternal) {
+		t.Error("Expected IsCategory to see the retryable base error")
+	}
+	if errors.IsCategory(multi, errors.CategoryNotFound) || !errors.HasCategory(multi, errors.CategoryNotFound) {
+		t.Error("Expected IsCategory to check the outermost error and HasCategory the tree")
+	}
+}
+
+func TestRootCategoryModes(t *testing.T) {
+	t.Cleanup(func() { errors.SetRootCategoryMode(errors.RootCategoryLegacy) })
+
+	err := errors.Wrap(fmt.Errorf("connection reset"), errors.CategoryExternal, "fetch rates")
+
+	if errors.GetRootCategoryMode() != errors.RootCategoryLegacy {
+		t.Fatal("Expected legacy mode by default")
+	}
+	if got := errors.RootCategory(err); got != errors.CategoryInternal {
+		t.Errorf("Expected legacy root category internal, got %s", got)
+	}
+
+	errors.SetRootCategoryMode(errors.RootCategoryInnermost)
+	if got := errors.RootCategory(err); got != errors.CategoryExternal {
+		t.Errorf("Expected innermost category external, got %s", got)
+	}
+	if got := errors.RootCategory(fmt.Errorf("plain")); got != errors.CategoryInternal {
+		t.Errorf("Expected internal for plain errors, got %s", got)
+	}
+}
@@ -0,0 +1,353 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CategoryDefinition declares the defaults of a category: the HTTP
+// status and gRPC code of its errors without a code, the severity set
+// by New and Wrap, whether its errors are retryable and the generic
+// client facing message.
+//
+//	errors.MustRegisterCategory(errors.NewCategoryDefinition("payment").
+//		WithHTTPStatus(402).
+//		WithGRPCCode(errors.GRPCCodeFailedPrecondition).
+//		WithPublicMessage("The payment could not be processed"))
+type CategoryDefinition struct {
+	Category      Category
+	Parent        Category
+	HTTPStatus    int
+	GRPCCode      GRPCCode
+	Severity      Severity
+	Retryable     bool
+	PublicMessage string
+	Description   string
+}
+
+// NewCategoryDefinition creates a definition with the defaults of
+// unknown categories: status 500, gRPC Unknown and SeverityError
+func NewCategoryDefinition(category Category) *CategoryDefinition {
+	return &CategoryDefinition{
+		Category:   category,
+		HTTPStatus: http.StatusInternalServerError,
+		GRPCCode:   GRPCCodeUnknown,
+		Severity:   SeverityError,
+	}
+}
+
+// WithParent makes the category a sub-category of parent, by default the
+// parent is the longest registered category its name extends
+func (d *CategoryDefinition) WithParent(parent Category) *CategoryDefinition {
+	d.Parent = parent
+	return d
+}
+
+// WithHTTPStatus sets the HTTP status of errors of the category without a code
+func (d *CategoryDefinition) WithHTTPStatus(status int) *CategoryDefinition {
+	d.HTTPStatus = status
+	return d
+}
+
+// WithGRPCCode sets the gRPC code of errors of the category
+func (d *CategoryDefinition) WithGRPCCode(code GRPCCode) *CategoryDefinition {
+	d.GRPCCode = code
+	return d
+}
+
+// WithSeverity sets the severity of errors created with New and Wrap
+func (d *CategoryDefinition) WithSeverity(s Severity) *CategoryDefinition {
+	d.Severity = s
+	return d
+}
+
+// WithRetryable sets whether errors of the category are retryable
+func (d *CategoryDefinition) WithRetryable(retryable bool) *CategoryDefinition {
+	d.Retryable = retryable
+	return d
+}
+
+// WithPublicMessage sets the generic client facing message of the category
+func (d *CategoryDefinition) WithPublicMessage(message string) *CategoryDefinition {
+	d.PublicMessage = message
+	return d
+}
+
+// WithDescription sets the documentation of the category
+func (d *CategoryDefinition) WithDescription(description string) *CategoryDefinition {
+	d.Description = description
+	return d
+}
+
+// CategoryRegistry is a thread-safe registry of category definitions.
+// It also maps HTTP statuses back to categories for HTTPStatusToCategory
+type CategoryRegistry struct {
+	mu         sync.RWMutex
+	categories map[Category]CategoryDefinition
+	statuses   map[int]Category
+}
+
+// DefaultCategoryRegistry holds the built-in categories and any category
+// registered through RegisterCategory or MustRegisterCategory
+var DefaultCategoryRegistry = newDefaultCategoryRegistry()
+
+// NewCategoryRegistry creates an empty CategoryRegistry
+func NewCategoryRegistry() *CategoryRegistry {
+	return &CategoryRegistry{
+		categories: make(map[Category]CategoryDefinition),
+		statuses:   make(map[int]Category),
+	}
+}
+
+// Register adds a definition. Its HTTP status is mapped back to the
+// category when no other category claimed it. It returns an error if
+// the category is empty or already registered
+func (r *CategoryRegistry) Register(def *CategoryDefinition) error {
+	if def == nil || def.Category == "" {
+		return fmt.Errorf("category registry: category is required")
+	}
+	if def.Parent == def.Category {
+		return fmt.Errorf("category registry: category %s cannot be its own parent", def.Category)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.categories[def.Category]; ok {
+		return fmt.Errorf("category registry: duplicate category %s", def.Category)
+	}
+
+	r.categories[def.Category] = *def
+	if _, claimed := r.statuses[def.HTTPStatus]; !claimed && def.HTTPStatus != 0 {
+		r.statuses[def.HTTPStatus] = def.Category
+	}
+	return nil
+}
+
+// MustRegister adds all definitions and panics on the first invalid or
+// duplicate one. Meant to be called from init
+func (r *CategoryRegistry) MustRegister(defs ...*CategoryDefinition) {
+	for _, def := range defs {
+		if err := r.Register(def); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Lookup returns the definition registered for category
+func (r *CategoryRegistry) Lookup(category Category) (CategoryDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, ok := r.categories[category]
+	return def, ok
+}
+
+// Definition returns the definition registered for category. Unregistered
+// sub-categories inherit the definition of their parent, other categories
+// get the defaults of NewCategoryDefinition
+func (r *CategoryRegistry) Definition(category Category) CategoryDefinition {
+	if def, ok := r.Lookup(category); ok {
+		return def
+	}
+	if parent := r.Parent(category); parent != "" {
+		def := r.Definition(parent)
+		def.Category = category
+		def.Parent = parent
+		return def
+	}
+	return *NewCategoryDefinition(category)
+}
+
+// Parent returns the parent of category: the parent it was registered
+// with, or else the longest registered category it extends. Categories
+// without a parent return an empty category
+func (r *CategoryRegistry) Parent(category Category) Category {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if def, ok := r.categories[category]; ok && def.Parent != "" {
+		return def.Parent
+	}
+
+	var parent Category
+	for registered := range r.categories {
+		if len(registered) > len(parent) && strings.HasPrefix(string(category), string(registered)+"_") {
+			parent = registered
+		}
+	}
+	return parent
+}
+
+// Update changes the definition of category, registering it with the
+// defaults first if needed. The HTTP status mapping is left unchanged
+func (r *CategoryRegistry) Update(category Category, fn func(*CategoryDefinition)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	def, ok := r.categories[category]
+	if !ok {
+		def = *NewCategoryDefinition(category)
+	}
+	fn(&def)
+	def.Category = category
+	r.categories[category] = def
+}
+
+// Unregister removes the definition of category and the HTTP status
+// mappings pointing to it
+func (r *CategoryRegistry) Unregister(category Category) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.categories, category)
+	for status, mapped := range r.statuses {
+		if mapped == category {
+			delete(r.statuses, status)
+		}
+	}
+}
+
+// Categories returns the registered categories, sorted
+func (r *CategoryRegistry) Categories() []Category {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	categories := make([]Category, 0, len(r.categories))
+	for category := range r.categories {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i] < categories[j]
+	})
+	return categories
+}
+
+// MapHTTPStatus makes status map back to category in CategoryForHTTPStatus
+func (r *CategoryRegistry) MapHTTPStatus(status int, category Category) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statuses[status] = category
+}
+
+// CategoryForHTTPStatus returns the category mapped to status, other
+// 4xx statuses are bad input and anything else internal
+func (r *CategoryRegistry) CategoryForHTTPStatus(status int) Category {
+	r.mu.RLock()
+	category, ok := r.statuses[status]
+	r.mu.RUnlock()
+
+	switch {
+	case ok:
+		return category
+	case status >= 400 && status < 500:
+		return CategoryBadInput
+	default:
+		return CategoryInternal
+	}
+}
+
+// RegisterCategory adds a definition to the DefaultCategoryRegistry
+func RegisterCategory(def *CategoryDefinition) error {
+	return DefaultCategoryRegistry.Register(def)
+}
+
+// MustRegisterCategory adds definitions to the DefaultCategoryRegistry,
+// panics on duplicates
+func MustRegisterCategory(defs ...*CategoryDefinition) {
+	DefaultCategoryRegistry.MustRegister(defs...)
+}
+
+// LookupCategory returns the definition registered in the
+// DefaultCategoryRegistry for category
+func LookupCategory(category Category) (CategoryDefinition, bool) {
+	return DefaultCategoryRegistry.Lookup(category)
+}
+
+func newDefaultCategoryRegistry() *CategoryRegistry {
+	r := NewCategoryRegistry()
+
+	// statuses shared by several categories map to the generic ones, and
+	// every 5xx status stays internal like 503 and 504
+	r.statuses[http.StatusBadRequest] = CategoryBadInput
+	r.statuses[http.StatusInternalServerError] = CategoryInternal
+	r.statuses[http.StatusBadGateway] = CategoryInternal
+
+	r.MustRegister(
+		NewCategoryDefinition(CategoryValidation).
+			WithHTTPStatus(http.StatusBadRequest).
+			WithGRPCCode(GRPCCodeInvalidArgument).
+			WithPublicMessage("The request contains invalid data").
+			WithDescription("The request failed field level validation"),
+		NewCategoryDefinition(CategoryAuth).
+			WithHTTPStatus(http.StatusUnauthorized).
+			WithGRPCCode(GRPCCodeUnauthenticated).
+			WithPublicMessage("Authentication is required").
+			WithDescription("The caller is not authenticated"),
+		NewCategoryDefinition(CategoryAuthz).
+			WithHTTPStatus(http.StatusForbidden).
+			WithGRPCCode(GRPCCodePermissionDenied).
+			WithPublicMessage("You are not allowed to perform this action").
+			WithDescription("The caller is not allowed to perform the action"),
+		NewCategoryDefinition(CategoryOperation).
+			WithGRPCCode(GRPCCodeInternal).
+			WithPublicMessage("The operation could not be completed").
+			WithDescription("An operation failed while being processed"),
+		NewCategoryDefinition(CategoryNotFound).
+			WithHTTPStatus(http.StatusNotFound).
+			WithGRPCCode(GRPCCodeNotFound).
+			WithPublicMessage("The requested resource was not found").
+			WithDescription("The requested resource does not exist"),
+		NewCategoryDefinition(CategoryConflict).
+			WithHTTPStatus(http.StatusConflict).
+			WithGRPCCode(GRPCCodeAlreadyExists).
+			WithPublicMessage("The request conflicts with the current state of the resource").
+			WithDescription("The request conflicts with the current state of the resource"),
+		NewCategoryDefinition(CategoryRateLimit).
+			WithHTTPStatus(http.StatusTooManyRequests).
+			WithGRPCCode(GRPCCodeResourceExhausted).
+			WithRetryable(true).
+			WithPublicMessage("Too many requests, try again later").
+			WithDescription("The caller exceeded a rate limit or quota"),
+		NewCategoryDefinition(CategoryBadInput).
+			WithHTTPStatus(http.StatusBadRequest).
+			WithGRPCCode(GRPCCodeInvalidArgument).
+			WithPublicMessage("The request is invalid").
+			WithDescription("The request is malformed or invalid"),
+		NewCategoryDefinition(CategoryInternal).
+			WithGRPCCode(GRPCCodeInternal).
+			WithPublicMessage(DefaultPublicMessage).
+			WithDescription("An unexpected server side failure"),
+		NewCategoryDefinition(CategoryExternal).
+			WithHTTPStatus(http.StatusBadGateway).
+			WithGRPCCode(GRPCCodeUnavailable).
+			WithPublicMessage("A dependent service is unavailable").
+			WithDescription("A dependent service failed or is unavailable"),
+		NewCategoryDefinition(CategoryMiddleware).
+			WithGRPCCode(GRPCCodeInternal).
+			WithPublicMessage("The request could not be processed").
+			WithDescription("A middleware failed while processing the request"),
+		NewCategoryDefinition(CategoryRouting).
+			WithHTTPStatus(http.StatusNotFound).
+			WithGRPCCode(GRPCCodeUnimplemented).
+			WithPublicMessage("The requested route does not exist").
+			WithDescription("No route matches the request"),
+		NewCategoryDefinition(CategoryHandler).
+			WithGRPCCode(GRPCCodeInternal).
+			WithPublicMessage("The request could not be processed").
+			WithDescription("A request handler failed"),
+		NewCategoryDefinition(CategoryMethodNotAllowed).
+			WithHTTPStatus(http.StatusMethodNotAllowed).
+			WithGRPCCode(GRPCCodeUnimplemented).
+			WithPublicMessage("The method is not allowed for this resource").
+			WithDescription("The method is not supported by the resource"),
+		NewCategoryDefinition(CategoryCommand).
+			WithGRPCCode(GRPCCodeInternal).
+			WithPublicMessage("The command could not be completed").
+			WithDescription("A command failed"),
+	)
+
+	return r
+}
@@ -0,0 +1,221 @@
+package errors_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goliatone/go-errors"
+)
+
+func TestCategoryRegistryRegister(t *testing.T) {
+	registry := errors.NewCategoryRegistry()
+	payment := errors.CategoryOperation.Extend("payment")
+
+	err := registry.Register(errors.NewCategoryDefinition(payment).
+		WithHTTPStatus(http.StatusPaymentRequired).
+		WithGRPCCode(errors.GRPCCodeFailedPrecondition).
+		WithSeverity(errors.SeverityWarning).
+		WithRetryable(true).
+		WithPublicMessage("Payment failed").
+		WithDescription("The charge was declined"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	def, ok := registry.Lookup(payment)
+	if !ok {
+		t.Fatal("Expected category to be registered")
+	}
+	if def.HTTPStatus != http.StatusPaymentRequired || def.GRPCCode != errors.GRPCCodeFailedPrecondition {
+		t.Errorf("Expected 402/FailedPrecondition, got %d/%s", def.HTTPStatus, def.GRPCCode)
+	}
+	if def.Severity != errors.SeverityWarning || !def.Retryable {
+		t.Errorf("Expected retryable warning, got %s/%v", def.Severity, def.Retryable)
+	}
+	if def.PublicMessage != "Payment failed" || def.Description != "The charge was declined" {
+		t.Errorf("Unexpected messages %q/%q", def.PublicMessage, def.Description)
+	}
+
+	if got := registry.CategoryForHTTPStatus(http.StatusPaymentRequired); got != payment {
+		t.Errorf("Expected 402 to map to %s, got %s", payment, got)
+	}
+
+	if err := registry.Register(errors.NewCategoryDefinition(payment)); err == nil {
+		t.Error("Expected duplicate registration to fail")
+	}
+	if err := registry.Register(errors.NewCategoryDefinition("")); err == nil {
+		t.Error("Expected empty category to fail")
+	}
+
+	if got := registry.Categories(); len(got) != 1 || got[0] != payment {
+		t.Errorf("Expected [%s], got %v", payment, got)
+	}
+}
+
+func TestCategoryRegistryDefaults(t *testing.T) {
+	registry := errors.NewCategoryRegistry()
+
+	def := registry.Definition("unknown")
+	if def.Category != "unknown" || def.HTTPStatus != http.StatusInternalServerError {
+		t.Errorf("Expected unknown/500, got %s/%d", def.Category, def.HTTPStatus)
+	}
+	if def.GRPCCode != errors.GRPCCodeUnknown || def.Severity != errors.SeverityError || def.Retryable {
+		t.Errorf("Unexpected defaults %s/%s/%v", def.GRPCCode, def.Severity, def.Retryable)
+	}
+
+	registry.Update("unknown", func(def *errors.CategoryDefinition) {
+		def.HTTPStatus = http.StatusTeapot
+	})
+	if got := registry.Definition("unknown").HTTPStatus; got != http.StatusTeapot {
+		t.Errorf("Expected updated status 418, got %d", got)
+	}
+
+	tests := []struct {
+		status int
+		want   errors.Category
+	}{
+		{http.StatusTeapot, errors.CategoryBadInput},
+		{http.StatusServiceUnavailable, errors.CategoryInternal},
+	}
+	for _, tt := range tests {
+		if got := registry.CategoryForHTTPStatus(tt.status); got != tt.want {
+			t.Errorf("Expected %d to map to %s, got %s", tt.status, tt.want, got)
+		}
+	}
+
+	registry.MapHTTPStatus(http.StatusServiceUnavailable, errors.CategoryExternal)
+	if got := registry.CategoryForHTTPStatus(http.StatusServiceUnavailable); got != errors.CategoryExternal {
+		t.Errorf("Expected 503 to map to external, got %s", got)
+	}
+}
+
+func TestDefaultCategoryRegistry(t *testing.T) {
+	tests := []struct {
+		category  errors.Category
+		status    int
+		grpc      errors.GRPCCode
+		retryable bool
+	}{
+		{errors.CategoryValidation, http.StatusBadRequest, errors.GRPCCodeInvalidArgument, false},
+		{errors.CategoryAuth, http.StatusUnauthorized, errors.GRPCCodeUnauthenticated, false},
+		{errors.CategoryNotFound, http.StatusNotFound, errors.GRPCCodeNotFound, false},
+		{errors.CategoryRateLimit, http.StatusTooManyRequests, errors.GRPCCodeResourceExhausted, true},
+		{errors.CategoryInternal, http.StatusInternalServerError, errors.GRPCCodeInternal, false},
+	}
+
+	for _, tt := range tests {
+		def, ok := errors.LookupCategory(tt.category)
+		if !ok {
+			t.Errorf("Expected %s to be registered", tt.category)
+			continue
+		}
+		if def.HTTPStatus != tt.status || def.GRPCCode != tt.grpc || def.Retryable != tt.retryable {
+			t.Errorf("%s: expected %d/%s/%v, got %d/%s/%v", tt.category,
+				tt.status, tt.grpc, tt.retryable, def.HTTPStatus, def.GRPCCode, def.Retryable)
+		}
+		if def.PublicMessage == "" || def.Description == "" {
+			t.Errorf("%s: expected public message and description", tt.category)
+		}
+	}
+
+	if got := errors.HTTPStatusToCategory(http.StatusNotFound); got != errors.CategoryNotFound {
+		t.Errorf("Expected 404 to map to not_found, got %s", got)
+	}
+	if got := errors.HTTPStatusToCategory(http.StatusBadRequest); got != errors.CategoryBadInput {
+		t.Errorf("Expected 400 to map to bad_input, got %s", got)
+	}
+	for _, status := range []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		if got := errors.HTTPStatusToCategory(status); got != errors.CategoryInternal {
+			t.Errorf("Expected %d to stay internal, got %s", status, got)
+		}
+	}
+}
+
+func TestCustomCategoryDefaults(t *testing.T) {
+	quota := errors.CategoryRateLimit.Extend("quota")
+	errors.MustRegisterCategory(errors.NewCategoryDefinition(quota).
+		WithHTTPStatus(http.StatusTooManyRequests).
+		WithSeverity(errors.SeverityWarning).
+		WithRetryable(true).
+		WithPublicMessage("Quota exceeded"))
+	t.Cleanup(func() { errors.DefaultCategoryRegistry.Unregister(quota) })
+
+	err := errors.New("monthly quota exceeded", quota)
+	if err.Severity != errors.SeverityWarning {
+		t.Errorf("Expected warning severity from the registry, got %s", err.Severity)
+	}
+	if got := err.HTTPStatus(); got != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", got)
+	}
+	if !errors.IsRetryableError(err) {
+		t.Error("Expected error to be retryable from its category")
+	}
+	if got := errors.CategoryPublicMessage(quota); got != "Quota exceeded" {
+		t.Errorf("Expected category public message, got %q", got)
+	}
+
+	response := err.ToErrorResponse(false, nil)
+	if response.Error.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected response code 429, got %d", response.Error.Code)
+	}
+
+	// 429 is claimed by the built-in rate limit category
+	if got := errors.HTTPStatusToCategory(http.StatusTooManyRequests); got != errors.CategoryRateLimit {
+		t.Errorf("Expected 429 to keep mapping to rate_limit, got %s", got)
+	}
+
+	wrapped := errors.Wrap(fmt.Errorf("quota service"), quota, "check failed")
+	if wrapped.Severity != errors.SeverityWarning {
+		t.Errorf("Expected Wrap to use the category severity, got %s", wrapped.Severity)
+	}
+
+	if errors.IsRetryableError(errors.New("boom", errors.CategoryInternal)) {
+		t.Error("Expected internal errors not to be retryable")
+	}
+}
+
+func TestCategoryRetryableSeverityRule(t *testing.T) {
+	if !errors.IsRetryableError(errors.New("slow down", errors.CategoryRateLimit)) {
+		t.Error("Expected rate limit errors to be retryable from their category")
+	}
+	if errors.IsRetryableError(errors.NewCritical("slow down", errors.CategoryRateLimit)) {
+		t.Error("Expected critical errors to never be retryable")
+	}
+
+	if !errors.KindTooManyAttempts.Retryable || !errors.IsRetryableError(errors.KindTooManyAttempts.New()) {
+		t.Error("Expected rate limit kinds to default to retryable from their category")
+	}
+	if errors.IsRetryableError(errors.KindTokenExpired.New()) {
+		t.Error("Expected auth kinds to stay non retryable")
+	}
+}
+
+func TestConstructorsUseCategorySeverity(t *testing.T) {
+	category := errors.Category("constructor_severity_test")
+	errors.MustRegisterCategory(errors.NewCategoryDefinition(category).WithSeverity(errors.SeverityWarning))
+	t.Cleanup(func() { errors.DefaultCategoryRegistry.Unregister(category) })
+
+	if got := errors.WrapAll(category, "batch", fmt.Errorf("a")).Severity; got != errors.SeverityWarning {
+		t.Errorf("Expected WrapAll to use the category severity, got %s", got)
+	}
+	if got := errors.NewKind(category, "SEVERITY_TEST").Severity; got != errors.SeverityWarning {
+		t.Errorf("Expected NewKind to use the category severity, got %s", got)
+	}
+
+	original, _ := errors.LookupCategory(errors.CategoryValidation)
+	t.Cleanup(func() {
+		errors.DefaultCategoryRegistry.Update(errors.CategoryValidation, func(def *errors.CategoryDefinition) {
+			*def = original
+		})
+	})
+	errors.DefaultCategoryRegistry.Update(errors.CategoryValidation, func(def *errors.CategoryDefinition) {
+		def.Severity = errors.SeverityInfo
+	})
+	if got := errors.NewValidation("invalid").Severity; got != errors.SeverityInfo {
+		t.Errorf("Expected NewValidation to use the category severity, got %s", got)
+	}
+	if got := errors.NewValidationFromMap("invalid", map[string]string{"a": "b"}).Severity; got != errors.SeverityInfo {
+		t.Errorf("Expected NewValidationFromMap to use the category severity, got %s", got)
+	}
+}
@@ -0,0 +1,218 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxErrorBodySize limits how much of an upstream error body is read
+const DefaultMaxErrorBodySize = 64 << 10
+
+// Metadata keys set on errors built from upstream responses
+const (
+	MetadataUpstreamHost   = "upstream_host"
+	MetadataUpstreamMethod = "upstream_method"
+	MetadataUpstreamPath   = "upstream_path"
+	MetadataUpstreamStatus = "upstream_status"
+)
+
+// FromHTTPResponse converts an upstream error response (status 400 and
+// above) into a CategoryExternal error, nil for any other status.
+//
+// A body holding our ErrorResponse envelope or RFC 9457 problem details
+// is parsed into the Source of the error, otherwise the Source gets the
+// category and text code inferred from the status and the body text as
+// message. The error keeps the upstream text code and the origin as
+// metadata (host, method, path, status), its Code is left unset so the
+// External category status (502) is answered instead of the upstream
+// one. The upstream metadata keys are in InternalMetadataKeys. Retryable statuses
+// (408, 429, 502, 503, 504) and responses with Retry-After return a
+// *RetryableError delayed by Retry-After. The body is read up to
+// DefaultMaxErrorBodySize and replaced, so callers can still read it
+func FromHTTPResponse(resp *http.Response) error {
+	return fromHTTPResponse(resp, DefaultMaxErrorBodySize)
+}
+
+func fromHTTPResponse(resp *http.Response, maxBodySize int64) error {
+	if resp == nil || resp.StatusCode < 400 {
+		return nil
+	}
+
+	body := readErrorBody(resp, maxBodySize)
+	upstream := parseUpstreamError(resp, body)
+
+	textCode := upstream.TextCode
+	if textCode == "" {
+		textCode = HTTPStatusToTextCode(resp.StatusCode)
+	}
+
+	metadata := map[string]any{MetadataUpstreamStatus: resp.StatusCode}
+	if req := resp.Request; req != nil {
+		metadata[MetadataUpstreamMethod] = req.Method
+		if req.URL != nil {
+			metadata[MetadataUpstreamHost] = req.URL.Host
+			metadata[MetadataUpstreamPath] = req.URL.Path
+		}
+	}
+
+	host := "upstream"
+	if h, ok := metadata[MetadataUpstreamHost].(string); ok && h != "" {
+		host = h
+	}
+
+	e := &Error{
+		Category:  CategoryExternal,
+		TextCode:  textCode,
+		Message:   fmt.Sprintf("%s responded %d %s", host, resp.StatusCode, http.StatusText(resp.StatusCode)),
+		Source:    upstream,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+		Location:  captureLocation(2),
+		Severity:  categorySeverity(CategoryExternal),
+	}
+
+	delay, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	if !hasRetryAfter && !retryableStatus(resp.StatusCode) {
+		return e
+	}
+
+	retryErr := &RetryableError{BaseError: e, retryable: true, baseDelay: time.Second}
+	if hasRetryAfter {
+		retryErr.baseDelay = delay
+		e.Metadata["retry_after"] = delay.String()
+	}
+	return retryErr
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter reads delay-seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+func readErrorBody(resp *http.Response, maxBodySize int64) []byte {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	rest := resp.Body
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), rest), rest}
+	return body
+}
+
+// parseUpstreamError decodes the body as problem details or as our
+// envelope, falling back to an error inferred from the status
+func parseUpstreamError(resp *http.Response, body []byte) *Error {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	if mediaType == ProblemContentType {
+		if e, err := ParseProblem(body); err == nil {
+			return e
+		}
+	}
+
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		var envelope struct {
+			Error *Error `json:"error"`
+		}
+		if err := json.Unmarshal(body, &envelope); err == nil && envelope.Error != nil && envelope.Error.Category != "" {
+			if envelope.Error.Code == 0 {
+				envelope.Error.Code = resp.StatusCode
+			}
+			return envelope.Error
+		}
+	}
+
+	message := strings.TrimSpace(string(body))
+	if message == "" || mediaType == "text/html" {
+		message = http.StatusText(resp.StatusCode)
+	}
+	if len(message) > 512 {
+		message = message[:512] + "..."
+	}
+
+	category := HTTPStatusToCategory(resp.StatusCode)
+	return &Error{
+		Category:  category,
+		Code:      resp.StatusCode,
+		TextCode:  HTTPStatusToTextCode(resp.StatusCode),
+		Message:   message,
+		Timestamp: time.Now(),
+		Severity:  categorySeverity(category),
+	}
+}
+
+// Transport is an http.RoundTripper that turns upstream error responses
+// into errors with FromHTTPResponse. The response body is closed and
+// the client returns the error wrapped in a *url.Error, As and the
+// category sentinels see through it
+//
+//	client := &http.Client{Transport: errors.NewTransport(nil)}
+type Transport struct {
+	// Base performs the requests, http.DefaultTransport when nil
+	Base http.RoundTripper
+	// MaxBodySize limits the error body read, DefaultMaxErrorBodySize when zero
+	MaxBodySize int64
+}
+
+// NewTransport wraps base, http.DefaultTransport when nil
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode < 400 {
+		return resp, err
+	}
+
+	maxBodySize := t.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxErrorBodySize
+	}
+
+	upstreamErr := fromHTTPResponse(resp, maxBodySize)
+	if resp.Body != nil {
+		resp.Body.Close()
+	}
+	return nil, upstreamErr
+}
@@ -0,0 +1,174 @@
+package errors_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-errors"
+)
+
+func newUpstream(t *testing.T, status int, contentType, body string, headers map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFromHTTPResponse_Envelope(t *testing.T) {
+	upstream := errors.New("user 7 missing", errors.CategoryNotFound).WithCode(404).WithTextCode("USER_NOT_FOUND")
+	data, _ := upstream.MarshalJSON()
+	server := newUpstream(t, 404, "application/json", `{"error":`+string(data)+`}`, nil)
+
+	resp, err := http.Get(server.URL + "/users/7")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	converted := errors.FromHTTPResponse(resp)
+	var e *errors.Error
+	if !errors.As(converted, &e) {
+		t.Fatalf("Expected *Error, got %T", converted)
+	}
+
+	if e.Category != errors.CategoryExternal {
+		t.Errorf("Expected external category, got %s", e.Category)
+	}
+	if e.HTTPStatus() != http.StatusBadGateway || e.TextCode != "USER_NOT_FOUND" {
+		t.Errorf("Expected 502/USER_NOT_FOUND, got %d/%s", e.HTTPStatus(), e.TextCode)
+	}
+	if e.Metadata[errors.MetadataUpstreamStatus] != 404 {
+		t.Errorf("Expected upstream status in metadata, got %v", e.Metadata[errors.MetadataUpstreamStatus])
+	}
+	if e.Metadata[errors.MetadataUpstreamMethod] != http.MethodGet || e.Metadata[errors.MetadataUpstreamPath] != "/users/7" {
+		t.Errorf("Expected origin metadata, got %v", e.Metadata)
+	}
+	if e.Metadata[errors.MetadataUpstreamHost] != resp.Request.URL.Host {
+		t.Errorf("Expected upstream host, got %v", e.Metadata[errors.MetadataUpstreamHost])
+	}
+	if !errors.Is(converted, errors.ErrNotFound) {
+		t.Error("Expected the upstream not found error in the chain")
+	}
+	if errors.IsRetryableError(converted) {
+		t.Error("Expected 404 not to be retryable")
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if len(body) == 0 {
+		t.Error("Expected the body to stay readable")
+	}
+}
+
+func TestFromHTTPResponse_Problem(t *testing.T) {
+	server := newUpstream(t, 409, errors.ProblemContentType,
+		`{"title":"Conflict","status":409,"detail":"version mismatch","text_code":"VERSION_CONFLICT"}`, nil)
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	converted := errors.FromHTTPResponse(resp)
+	var e *errors.Error
+	if !errors.As(converted, &e) || e.TextCode != "VERSION_CONFLICT" {
+		t.Fatalf("Expected problem text code, got %v", converted)
+	}
+	if !errors.Is(converted, errors.ErrConflict) {
+		t.Error("Expected conflict category from the problem status")
+	}
+}
+
+func TestFromHTTPResponse_PlainAndRetryable(t *testing.T) {
+	server := newUpstream(t, 503, "text/plain", "maintenance window", map[string]string{"Retry-After": "3"})
+
+	resp, err := http.Get(server.URL + "/health")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	converted := errors.FromHTTPResponse(resp)
+	var retryErr *errors.RetryableError
+	if !errors.As(converted, &retryErr) {
+		t.Fatalf("Expected *RetryableError, got %T", converted)
+	}
+	if !retryErr.IsRetryable() {
+		t.Error("Expected 503 to be retryable")
+	}
+	if delay := retryErr.RetryDelay(0); delay != 3*time.Second {
+		t.Errorf("Expected Retry-After delay of 3s, got %s", delay)
+	}
+	if retryErr.TextCode != "SERVICE_UNAVAILABLE" {
+		t.Errorf("Expected inferred text code, got %s", retryErr.TextCode)
+	}
+
+	var source *errors.Error
+	if !errors.As(retryErr.BaseError.Source, &source) || source.Message != "maintenance window" {
+		t.Errorf("Expected body text as upstream message, got %v", retryErr.BaseError.Source)
+	}
+
+	if errors.FromHTTPResponse(&http.Response{StatusCode: 204}) != nil {
+		t.Error("Expected nil for success responses")
+	}
+}
+
+func TestFromHTTPResponse_ResponseHidesUpstream(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	server := newUpstream(t, 401, "text/plain", "token expired", nil)
+	resp, err := http.Get(server.URL + "/internal/billing")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	rec := httptest.NewRecorder()
+	errors.WriteHTTPError(rec, httptest.NewRequest(http.MethodGet, "/orders", nil), errors.FromHTTPResponse(resp))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("Expected 502 instead of the upstream status, got %d", rec.Code)
+	}
+	for _, key := range errors.InternalMetadataKeys {
+		if strings.Contains(rec.Body.String(), key) {
+			t.Errorf("Expected %s to be hidden in %s", key, rec.Body.String())
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := newUpstream(t, 429, "application/json", `{"message":"slow down"}`, nil)
+	client := &http.Client{Transport: errors.NewTransport(nil)}
+
+	resp, err := client.Get(server.URL + "/search")
+	if resp != nil {
+		resp.Body.Close()
+		t.Fatal("Expected no response for an error status")
+	}
+	if !errors.IsRetryableError(err) {
+		t.Errorf("Expected retryable error, got %v", err)
+	}
+	if !errors.Is(err, errors.ErrRateLimit) {
+		t.Error("Expected rate limit category from the status")
+	}
+
+	ok := newUpstream(t, 200, "text/plain", "fine", nil)
+	resp, err = client.Get(ok.URL)
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	resp.Body.Close()
+}
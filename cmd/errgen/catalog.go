@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/goliatone/go-errors"
+	"gopkg.in/yaml.v3"
+)
+
+// catalogFile is the declarative source of truth for text codes
+type catalogFile struct {
+	Package string      `yaml:"package" json:"package"`
+	Title   string      `yaml:"title" json:"title"`
+	Codes   []codeEntry `yaml:"codes" json:"codes"`
+}
+
+// codeEntry describes a single text code and its constructor
+type codeEntry struct {
+	Code      string `yaml:"code" json:"code"`
+	Category  string `yaml:"category" json:"category"`
+	Status    int    `yaml:"status" json:"status"`
+	Severity  string `yaml:"severity" json:"severity"`
+	Retryable bool   `yaml:"retryable" json:"retryable"`
+	Message   string `yaml:"message" json:"message"`
+	// PublicMessage is the client facing text, clients get the category
+	// generic message when empty
+	PublicMessage string       `yaml:"public_message" json:"public_message"`
+	Description   string       `yaml:"description" json:"description"`
+	Remediation   string       `yaml:"remediation" json:"remediation"`
+	DocsURL       string       `yaml:"docs_url" json:"docs_url"`
+	Params        []paramEntry `yaml:"params" json:"params"`
+}
+
+// paramEntry is a named constructor parameter, it can be referenced as
+// {name} in the message and is attached to the error metadata
+type paramEntry struct {
+	Name string `yaml:"name" json:"name"`
+	Type string `yaml:"type" json:"type"`
+}
+
+var (
+	codePattern        = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	placeholderPattern = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+	paramTypes         = map[string]bool{
+		"string": true, "int": true, "int64": true, "float64": true, "bool": true, "any": true,
+	}
+	severityNames = map[string]string{
+		"DEBUG":    "SeverityDebug",
+		"INFO":     "SeverityInfo",
+		"WARNING":  "SeverityWarning",
+		"ERROR":    "SeverityError",
+		"CRITICAL": "SeverityCritical",
+		"FATAL":    "SeverityFatal",
+	}
+	categoryNames = map[string]string{
+		"validation":         "CategoryValidation",
+		"authentication":     "CategoryAuth",
+		"authorization":      "CategoryAuthz",
+		"operation":          "CategoryOperation",
+		"not_found":          "CategoryNotFound",
+		"conflict":           "CategoryConflict",
+		"rate_limit":         "CategoryRateLimit",
+		"bad_input":          "CategoryBadInput",
+		"internal":           "CategoryInternal",
+		"external":           "CategoryExternal",
+		"middleware":         "CategoryMiddleware",
+		"routing":            "CategoryRouting",
+		"handler":            "CategoryHandler",
+		"method_not_allowed": "CategoryMethodNotAllowed",
+		"command":            "CategoryCommand",
+	}
+)
+
+// loadCatalog reads a YAML or JSON catalog file, the format is picked by extension
+func loadCatalog(path string) (*catalogFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog catalogFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &catalog)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &catalog)
+	default:
+		return nil, fmt.Errorf("unsupported catalog format %q, use .yaml, .yml or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	if err := catalog.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &catalog, nil
+}
+
+// validate checks the catalog and fills in defaults
+func (c *catalogFile) validate() error {
+	seen := make(map[string]bool)
+	for i := range c.Codes {
+		entry := &c.Codes[i]
+
+		if !codePattern.MatchString(entry.Code) {
+			return fmt.Errorf("codes[%d]: invalid code %q, expected UPPER_SNAKE_CASE", i, entry.Code)
+		}
+		if seen[entry.Code] {
+			return fmt.Errorf("codes[%d]: duplicate code %s", i, entry.Code)
+		}
+		seen[entry.Code] = true
+		// generated code registers every code in the DefaultCatalog at init
+		if errors.DefaultCatalog.IsKnown(entry.Code) {
+			return fmt.Errorf("codes[%d]: code %s is already defined by the errors package", i, entry.Code)
+		}
+
+		if entry.Category == "" {
+			return fmt.Errorf("%s: category is required", entry.Code)
+		}
+
+		if entry.Status != 0 && (entry.Status < 100 || entry.Status > 599) {
+			return fmt.Errorf("%s: invalid status %d, expected 100-599", entry.Code, entry.Status)
+		}
+
+		if entry.Message == "" {
+			entry.Message = entry.Description
+		}
+		if entry.Message == "" {
+			return fmt.Errorf("%s: message or description is required", entry.Code)
+		}
+
+		if entry.Severity == "" {
+			entry.Severity = "ERROR"
+		}
+		entry.Severity = strings.ToUpper(entry.Severity)
+		if _, ok := severityNames[entry.Severity]; !ok {
+			return fmt.Errorf("%s: unknown severity %q", entry.Code, entry.Severity)
+		}
+
+		declared := make(map[string]bool)
+		identifiers := make(map[string]string)
+		for j := range entry.Params {
+			param := &entry.Params[j]
+			if !token.IsIdentifier(param.Name) {
+				return fmt.Errorf("%s: invalid param name %q", entry.Code, param.Name)
+			}
+			if param.Type == "" {
+				param.Type = "string"
+			}
+			if !paramTypes[param.Type] {
+				return fmt.Errorf("%s: param %s has unsupported type %q", entry.Code, param.Name, param.Type)
+			}
+			if declared[param.Name] {
+				return fmt.Errorf("%s: duplicate param %s", entry.Code, param.Name)
+			}
+			declared[param.Name] = true
+
+			ident := paramName(param.Name)
+			if other, ok := identifiers[ident]; ok {
+				return fmt.Errorf("%s: params %s and %s both map to %s", entry.Code, other, param.Name, ident)
+			}
+			identifiers[ident] = param.Name
+		}
+
+		for _, match := range placeholderPattern.FindAllStringSubmatch(entry.Message, -1) {
+			if !declared[match[1]] {
+				return fmt.Errorf("%s: message references undeclared param {%s}", entry.Code, match[1])
+			}
+		}
+	}
+	return nil
+}
+
+// goName converts an UPPER_SNAKE_CASE code into an exported Go identifier
+func goName(code string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(strings.ToLower(code), "_") {
+		b.WriteString(exportPart(part))
+	}
+	return b.String()
+}
+
+// paramName converts a param name into an unexported Go identifier
+func paramName(name string) string {
+	parts := strings.Split(strings.ToLower(name), "_")
+
+	var b strings.Builder
+	for _, part := range parts {
+		if b.Len() == 0 {
+			b.WriteString(part)
+			continue
+		}
+		b.WriteString(exportPart(part))
+	}
+
+	result := b.String()
+	if result == "" {
+		return "arg"
+	}
+	if goKeywords[result] {
+		return result + "Value"
+	}
+	return result
+}
+
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "id": true, "ip": true, "json": true,
+	"sql": true, "uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+func exportPart(part string) string {
+	if part == "" {
+		return ""
+	}
+	if initialisms[part] {
+		return strings.ToUpper(part)
+	}
+	runes := []rune(part)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+	"err": true, "errors": true,
+}
+
+// formatMessage turns {name} placeholders into %v verbs and returns the
+// Go argument names in the order they appear. Messages without
+// placeholders are returned unchanged since Kind uses them verbatim
+func formatMessage(message string) (string, []string) {
+	if !placeholderPattern.MatchString(message) {
+		return message, nil
+	}
+
+	var args []string
+	format := strings.ReplaceAll(message, "%", "%%")
+	format = placeholderPattern.ReplaceAllStringFunc(format, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		args = append(args, paramName(name))
+		return "%v"
+	})
+	return format, args
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+)
+
+const generatedHeader = "Code generated by errgen. DO NOT EDIT."
+
+// goCode is the view of a code entry used by the Go template
+type goCode struct {
+	codeEntry
+	Name        string
+	CategoryRef string
+	SeverityRef string
+	Format      string
+	Args        []string
+	Signature   string
+	// Metadata is a map literal exposing the params by name
+	Metadata string
+}
+
+func newGoCode(entry codeEntry) goCode {
+	categoryRef := fmt.Sprintf("errors.Category(%q)", entry.Category)
+	if name, ok := categoryNames[entry.Category]; ok {
+		categoryRef = "errors." + name
+	}
+
+	formatStr, args := formatMessage(entry.Message)
+
+	params := make([]string, len(entry.Params))
+	metadata := make([]string, len(entry.Params))
+	for i, param := range entry.Params {
+		params[i] = paramName(param.Name) + " " + param.Type
+		metadata[i] = fmt.Sprintf("%q: %s", param.Name, paramName(param.Name))
+	}
+
+	var metadataLiteral string
+	if len(metadata) > 0 {
+		metadataLiteral = "map[string]any{" + strings.Join(metadata, ", ") + "}"
+	}
+
+	return goCode{
+		codeEntry:   entry,
+		Name:        goName(entry.Code),
+		CategoryRef: categoryRef,
+		SeverityRef: "errors." + severityNames[entry.Severity],
+		Format:      formatStr,
+		Args:        args,
+		Signature:   strings.Join(params, ", "),
+		Metadata:    metadataLiteral,
+	}
+}
+
+var goTemplate = template.Must(template.New("go").Parse(`// {{ .Header }}
+
+package {{ .Package }}
+
+import "github.com/goliatone/go-errors"
+
+// Text codes
+const (
+{{- range .Codes }}
+	TextCode{{ .Name }} = {{ printf "%q" .Code }}
+{{- end }}
+)
+
+// Kinds
+var (
+{{- range .Codes }}
+	Kind{{ .Name }} = errors.NewKind({{ .CategoryRef }}, TextCode{{ .Name }}).
+		WithCode({{ .Status }}).
+		WithSeverity({{ .SeverityRef }}).
+		WithRetryable({{ .Retryable }}).
+		{{- if .PublicMessage }}
+		WithPublicMessage({{ printf "%q" .PublicMessage }}).
+		{{- end }}
+		WithMessage({{ printf "%q" .Format }})
+{{- end }}
+)
+
+func init() {
+	errors.MustRegisterCode(
+{{- range .Codes }}
+		errors.CodeDefinition{
+			TextCode:    TextCode{{ .Name }},
+			Category:    {{ .CategoryRef }},
+			Status:      {{ .Status }},
+			Description: {{ printf "%q" .Description }},
+			{{- if .Remediation }}
+			Remediation: {{ printf "%q" .Remediation }},
+			{{- end }}
+			{{- if .DocsURL }}
+			DocsURL: {{ printf "%q" .DocsURL }},
+			{{- end }}
+		},
+{{- end }}
+	)
+}
+{{ range .Codes }}
+// New{{ .Name }} creates a {{ .Code }} error
+func New{{ .Name }}({{ .Signature }}) *errors.Error {
+	return Kind{{ .Name }}.New({{ range $i, $a := .Args }}{{ if $i }}, {{ end }}{{ $a }}{{ end }}).
+		{{- if .Metadata }}
+		WithMetadata({{ .Metadata }}).
+		{{- end }}
+		WithLocation(errors.CallerLocation(1))
+}
+
+// Wrap{{ .Name }} creates a {{ .Code }} error that wraps err
+func Wrap{{ .Name }}(err error{{ if .Signature }}, {{ .Signature }}{{ end }}) *errors.Error {
+	if err == nil {
+		return nil
+	}
+	return Kind{{ .Name }}.Wrap(err{{ range .Args }}, {{ . }}{{ end }}).
+		{{- if .Metadata }}
+		WithMetadata({{ .Metadata }}).
+		{{- end }}
+		WithLocation(errors.CallerLocation(1))
+}
+{{ end }}`))
+
+// generateGo renders Go constants, kinds, catalog registration and constructors
+func generateGo(catalog *catalogFile, pkg string) ([]byte, error) {
+	if pkg == "" {
+		pkg = catalog.Package
+	}
+	if pkg == "" {
+		return nil, fmt.Errorf("go package name is required, set package in the catalog or pass -pkg")
+	}
+
+	codes := make([]goCode, len(catalog.Codes))
+	for i, entry := range catalog.Codes {
+		codes[i] = newGoCode(entry)
+	}
+
+	var buf bytes.Buffer
+	err := goTemplate.Execute(&buf, map[string]any{
+		"Header":  generatedHeader,
+		"Package": pkg,
+		"Codes":   codes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated go: %w", err)
+	}
+	return formatted, nil
+}
+
+var tsTemplate = template.Must(template.New("ts").Parse(`// {{ .Header }}
+{{ if .Enum }}
+export enum TextCode {
+{{- range .Codes }}
+  {{ .Code }} = {{ printf "%q" .Code }},
+{{- end }}
+}
+{{ else }}
+export type TextCode =
+{{- range .Codes }}
+  | {{ printf "%q" .Code }}
+{{- end }};
+
+export const TextCodes = {
+{{- range .Codes }}
+  {{ .Code }}: {{ printf "%q" .Code }},
+{{- end }}
+} as const;
+{{ end }}
+export interface TextCodeDefinition {
+  category: string;
+  status: number;
+  description: string;
+}
+
+export const TextCodeDefinitions: Record<TextCode, TextCodeDefinition> = {
+{{- range .Codes }}
+  {{ printf "%q" .Code }}: { category: {{ printf "%q" .Category }}, status: {{ .Status }}, description: {{ printf "%q" .Description }} },
+{{- end }}
+};
+`))
+
+// generateTypeScript renders a union type (or enum) of the text codes
+func generateTypeScript(catalog *catalogFile, enum bool) ([]byte, error) {
+	var buf bytes.Buffer
+	err := tsTemplate.Execute(&buf, map[string]any{
+		"Header": generatedHeader,
+		"Enum":   enum,
+		"Codes":  catalog.Codes,
+	})
+	return buf.Bytes(), err
+}
+
+var markdownTemplate = template.Must(template.New("md").Funcs(template.FuncMap{
+	"cell": markdownCell,
+}).Parse(`<!-- {{ .Header }} -->
+
+# {{ .Title }}
+
+| Text code | Category | Status | Description |
+| --- | --- | --- | --- |
+{{- range .Codes }}
+| [` + "`{{ .Code }}`" + `](#{{ .Anchor }}) | {{ .Category }} | {{ .Status }} | {{ cell .Description }} |
+{{- end }}
+{{ range .Codes }}
+## {{ .Code }}
+
+- **Category:** ` + "`{{ .Category }}`" + `
+- **HTTP status:** {{ .Status }}
+- **Severity:** {{ .Severity }}
+- **Retryable:** {{ .Retryable }}
+{{- if .Message }}
+- **Message:** {{ .Message }}
+{{- end }}
+{{- if .PublicMessage }}
+- **Public message:** {{ .PublicMessage }}
+{{- end }}
+{{- if .Params }}
+- **Params:**{{ range .Params }} ` + "`{{ .Name }}`" + ` ({{ .Type }}){{ end }}
+{{- end }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{- if .Remediation }}
+**Remediation:** {{ .Remediation }}
+{{ end }}
+{{- if .DocsURL }}
+See {{ .DocsURL }}
+{{ end }}
+{{- end }}`))
+
+// generateMarkdown renders a reference page for the catalog
+func generateMarkdown(catalog *catalogFile) ([]byte, error) {
+	type mdCode struct {
+		codeEntry
+		Anchor string
+	}
+
+	codes := make([]mdCode, len(catalog.Codes))
+	for i, entry := range catalog.Codes {
+		codes[i] = mdCode{codeEntry: entry, Anchor: strings.ToLower(entry.Code)}
+	}
+
+	title := catalog.Title
+	if title == "" {
+		title = "Error Reference"
+	}
+
+	var buf bytes.Buffer
+	err := markdownTemplate.Execute(&buf, map[string]any{
+		"Header": generatedHeader,
+		"Title":  title,
+		"Codes":  codes,
+	})
+	return buf.Bytes(), err
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
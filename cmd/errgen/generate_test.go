@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCatalog_YAML(t *testing.T) {
+	catalog, err := loadCatalog("testdata/catalog.yaml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if catalog.Package != "apperrors" || len(catalog.Codes) != 3 {
+		t.Fatalf("Unexpected catalog: %+v", catalog)
+	}
+	if catalog.Codes[0].Severity != "WARNING" {
+		t.Errorf("Expected severity to be normalized, got %s", catalog.Codes[0].Severity)
+	}
+	if catalog.Codes[1].Severity != "ERROR" {
+		t.Errorf("Expected default severity ERROR, got %s", catalog.Codes[1].Severity)
+	}
+	if catalog.Codes[2].Message != catalog.Codes[2].Description {
+		t.Errorf("Expected message to default to description, got %q", catalog.Codes[2].Message)
+	}
+}
+
+func TestLoadCatalog_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	data := `{"package":"x","codes":[{"code":"A_B","category":"conflict","status":409,"message":"dup"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog, err := loadCatalog(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(catalog.Codes) != 1 || catalog.Codes[0].Status != 409 {
+		t.Errorf("Unexpected catalog: %+v", catalog)
+	}
+}
+
+func TestCatalogValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		codes []codeEntry
+		want  string
+	}{
+		{"invalid code", []codeEntry{{Code: "bad-code", Category: "internal", Message: "m"}}, "invalid code"},
+		{"duplicate code", []codeEntry{
+			{Code: "A", Category: "internal", Message: "m"},
+			{Code: "A", Category: "internal", Message: "m"},
+		}, "duplicate code A"},
+		{"missing category", []codeEntry{{Code: "A", Message: "m"}}, "category is required"},
+		{"missing message", []codeEntry{{Code: "A", Category: "internal"}}, "message or description is required"},
+		{"unknown severity", []codeEntry{{Code: "A", Category: "internal", Message: "m", Severity: "loud"}}, "unknown severity"},
+		{"undeclared param", []codeEntry{{Code: "A", Category: "internal", Message: "user {id}"}}, "undeclared param {id}"},
+		{"bad param type", []codeEntry{{Code: "A", Category: "internal", Message: "m",
+			Params: []paramEntry{{Name: "id", Type: "uuid"}}}}, "unsupported type"},
+		{"invalid param name", []codeEntry{{Code: "A", Category: "internal", Message: "m",
+			Params: []paramEntry{{Name: "user-id"}}}}, "invalid param name"},
+		{"colliding param names", []codeEntry{{Code: "A", Category: "internal", Message: "m",
+			Params: []paramEntry{{Name: "user_id"}, {Name: "USER_ID"}}}}, "both map to userID"},
+		{"built-in code", []codeEntry{{Code: "NOT_FOUND", Category: "not_found", Message: "m"}}, "already defined by the errors package"},
+		{"status out of range", []codeEntry{{Code: "A", Category: "internal", Message: "m", Status: 42}}, "invalid status 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := &catalogFile{Codes: tt.codes}
+			err := catalog.validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	catalog, err := loadCatalog("testdata/catalog.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := generateGo(catalog, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "gen.go", out, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v", err)
+	}
+
+	src := string(out)
+	for _, want := range []string{
+		"// Code generated by errgen. DO NOT EDIT.",
+		"package apperrors",
+		`TextCodeUserNotFound    = "USER_NOT_FOUND"`,
+		"errors.NewKind(errors.CategoryNotFound, TextCodeUserNotFound)",
+		`errors.NewKind(errors.Category("ledger"), TextCodeLedgerOutOfSync)`,
+		"WithSeverity(errors.SeverityWarning)",
+		`WithMessage("user %v not found")`,
+		`WithPublicMessage("The user was not found")`,
+		`WithMessage("quota of %v requests exceeded (100%% used)")`,
+		"func NewUserNotFound(userID string) *errors.Error",
+		"func WrapQuotaExceeded(err error, limit int) *errors.Error",
+		`WithMetadata(map[string]any{"user_id": userID})`,
+		"errors.MustRegisterCode(",
+		`DocsURL:     "https://docs.example.com/errors/USER_NOT_FOUND"`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected generated Go to contain %q", want)
+		}
+	}
+
+	if _, err := generateGo(&catalogFile{}, ""); err == nil {
+		t.Error("Expected error when no package name is available")
+	}
+}
+
+func TestParamNameAvoidsShadowing(t *testing.T) {
+	for name, want := range map[string]string{
+		"errors":  "errorsValue",
+		"err":     "errValue",
+		"type":    "typeValue",
+		"user_id": "userID",
+	} {
+		if got := paramName(name); got != want {
+			t.Errorf("paramName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	catalog, err := loadCatalog("testdata/catalog.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	union, err := generateTypeScript(catalog, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`export type TextCode =`, `  | "USER_NOT_FOUND"`, `QUOTA_EXCEEDED: "QUOTA_EXCEEDED",`} {
+		if !bytes.Contains(union, []byte(want)) {
+			t.Errorf("Expected union output to contain %q:\n%s", want, union)
+		}
+	}
+
+	enum, err := generateTypeScript(catalog, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(enum, []byte(`export enum TextCode {`)) || !bytes.Contains(enum, []byte(`USER_NOT_FOUND = "USER_NOT_FOUND",`)) {
+		t.Errorf("Unexpected enum output:\n%s", enum)
+	}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	catalog, err := loadCatalog("testdata/catalog.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := generateMarkdown(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := string(out)
+	for _, want := range []string{
+		"# Example Error Reference",
+		"| [`USER_NOT_FOUND`](#user_not_found) | not_found | 404 | The requested user does not exist |",
+		`The ledger \| reconciliation failed |`,
+		"## QUOTA_EXCEEDED",
+		"**Remediation:** Check the user ID",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected markdown to contain %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestRunGenerate(t *testing.T) {
+	dir := t.TempDir()
+	goOut := filepath.Join(dir, "errors_gen.go")
+	tsOut := filepath.Join(dir, "textCodes.ts")
+	mdOut := filepath.Join(dir, "ERRORS.md")
+
+	var stderr bytes.Buffer
+	err := run([]string{"gen", "-catalog", "testdata/catalog.yaml", "-go", goOut, "-ts", tsOut, "-md", mdOut}, &bytes.Buffer{}, &stderr)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v (%s)", err, stderr.String())
+	}
+
+	for _, path := range []string{goOut, tsOut, mdOut} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("Expected %s to be written", path)
+		}
+	}
+
+	if err := run([]string{"gen", "-catalog", "testdata/catalog.yaml"}, &bytes.Buffer{}, &stderr); err == nil {
+		t.Error("Expected error when no output is requested")
+	}
+	if err := run([]string{"bogus"}, &bytes.Buffer{}, &stderr); err == nil {
+		t.Error("Expected error for unknown command")
+	}
+}
+
+func TestRunSchema(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"schema", "-catalog", "testdata/catalog.yaml"}, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v (%s)", err, stderr.String())
+	}
+
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Responses map[string]json.RawMessage `json:"responses"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected JSON output, got error: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("Expected openapi 3.1.0, got %q", doc.OpenAPI)
+	}
+	if _, ok := doc.Components.Responses["LedgerError"]; !ok {
+		t.Error("Expected a response for the custom ledger category")
+	}
+	if !strings.Contains(string(doc.Components.Responses["NotFoundError"]), "USER_NOT_FOUND") {
+		t.Error("Expected USER_NOT_FOUND in the not found examples")
+	}
+
+	out := filepath.Join(t.TempDir(), "schema.yaml")
+	if err := run([]string{"schema", "-format", "jsonschema", "-o", out}, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "$defs:") {
+		t.Errorf("Expected YAML JSON Schema, got:\n%s", data)
+	}
+
+	if err := run([]string{"schema", "-format", "xml"}, &stdout, &stderr); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
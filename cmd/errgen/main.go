@@ -0,0 +1,192 @@
+// Command errgen generates error code artifacts from a declarative
+// YAML or JSON catalog.
+//
+//	errgen gen -catalog errors.yaml -go errors_gen.go -ts textCodes.ts -md ERRORS.md
+//	errgen schema -format openapi -catalog errors.yaml -o errors.openapi.yaml
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goliatone/go-errors"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "errgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		usage(stderr)
+		return fmt.Errorf("missing command")
+	}
+
+	switch args[0] {
+	case "gen", "generate":
+		return runGenerate(args[1:], stderr)
+	case "schema":
+		return runSchema(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return nil
+	default:
+		usage(stderr)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, `Usage: errgen <command> [flags]
+
+Commands:
+  gen       generate Go, TypeScript and Markdown from a catalog file
+  schema    emit a JSON Schema or OpenAPI 3.1 components for the error envelope
+
+Run "errgen <command> -h" for the command flags.`)
+}
+
+func runGenerate(args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	catalogPath := fs.String("catalog", "", "path to the YAML or JSON catalog (required)")
+	goOut := fs.String("go", "", "output path for the Go file")
+	pkg := fs.String("pkg", "", "Go package name, defaults to the catalog package")
+	tsOut := fs.String("ts", "", "output path for the TypeScript file")
+	tsEnum := fs.Bool("ts-enum", false, "emit a TypeScript enum instead of a union type")
+	mdOut := fs.String("md", "", "output path for the Markdown reference")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *catalogPath == "" {
+		fs.Usage()
+		return fmt.Errorf("-catalog is required")
+	}
+	if *goOut == "" && *tsOut == "" && *mdOut == "" {
+		return fmt.Errorf("nothing to generate, set at least one of -go, -ts or -md")
+	}
+
+	catalog, err := loadCatalog(*catalogPath)
+	if err != nil {
+		return err
+	}
+
+	outputs := []struct {
+		path   string
+		render func() ([]byte, error)
+	}{
+		{*goOut, func() ([]byte, error) { return generateGo(catalog, *pkg) }},
+		{*tsOut, func() ([]byte, error) { return generateTypeScript(catalog, *tsEnum) }},
+		{*mdOut, func() ([]byte, error) { return generateMarkdown(catalog) }},
+	}
+
+	for _, out := range outputs {
+		if out.path == "" {
+			continue
+		}
+		data, err := out.render()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(out.path, data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runSchema(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	format := fs.String("format", "openapi", "output format: openapi or jsonschema")
+	catalogPath := fs.String("catalog", "", "optional catalog whose codes are added to the response examples")
+	out := fs.String("o", "", "output path, .yaml or .yml writes YAML, defaults to JSON on stdout")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var doc map[string]any
+	switch *format {
+	case "openapi":
+		catalog, err := schemaCatalog(*catalogPath)
+		if err != nil {
+			return err
+		}
+		doc = map[string]any{
+			"openapi":    "3.1.0",
+			"components": errors.OpenAPIComponents(catalog),
+		}
+	case "jsonschema", "json-schema":
+		doc = errors.ErrorResponseJSONSchema()
+	default:
+		return fmt.Errorf("unknown schema format %q, use openapi or jsonschema", *format)
+	}
+
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(*out)) {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(doc)
+	default:
+		data, err = json.MarshalIndent(doc, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0o644)
+}
+
+// schemaCatalog returns the default catalog extended with the codes of
+// the catalog file at path, if any
+func schemaCatalog(path string) (*errors.Catalog, error) {
+	if path == "" {
+		return errors.DefaultCatalog, nil
+	}
+
+	file, err := loadCatalog(path)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := errors.NewCatalog()
+	for _, def := range errors.DefaultCatalog.Definitions() {
+		if err := catalog.Register(def); err != nil {
+			return nil, err
+		}
+	}
+	for _, entry := range file.Codes {
+		err := catalog.Register(errors.CodeDefinition{
+			TextCode:    entry.Code,
+			Category:    errors.Category(entry.Category),
+			Status:      entry.Status,
+			Description: entry.Description,
+			Remediation: entry.Remediation,
+			DocsURL:     entry.DocsURL,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return catalog, nil
+}
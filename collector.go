@@ -19,6 +19,16 @@ type ErrorCollector struct {
 type collectedError struct {
 	err       *Error
 	retryable *RetryableError
+	multi     *MultiError
+}
+
+// validationErrors returns the validation errors of the collected error,
+// including those of its source tree and causes
+func (c collectedError) validationErrors() ValidationErrors {
+	if c.multi != nil {
+		return c.multi.AllValidationErrors()
+	}
+	return c.err.AllValidationErrors()
 }
 
 // CollectorOption defines functional options for ErrorCollector configuration
@@ -96,6 +106,14 @@ func (c *ErrorCollector) Add(err error) bool {
 		return true
 	}
 
+	if multiErr, ok := err.(*MultiError); ok && multiErr.BaseError != nil {
+		c.errors = append(c.errors, collectedError{
+			err:   multiErr.BaseError,
+			multi: multiErr,
+		})
+		return true
+	}
+
 	var customErr *Error
 	if As(err, &customErr) {
 		c.errors = append(c.errors, collectedError{err: customErr})
@@ -148,49 +166,7 @@ func (c *ErrorCollector) Merge() *Error {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if len(c.errors) == 0 {
-		return nil
-	}
-
-	if len(c.errors) == 1 {
-		return c.errors[0].err.Clone()
-	}
-
-	// Create aggregate error with metadata about collected errors
-	categoryStats := c.categoryStatsUnsafe()
-	severityStats := c.severityDistributionUnsafe()
-
-	// Find the highest severity level
-	highestSeverity := SeverityDebug
-	for severity := range severityStats {
-		if severity > highestSeverity {
-			highestSeverity = severity
-		}
-	}
-
-	// Use the most common category, or CategoryInternal if tied
-	mostCommonCategory := c.mostCommonCategoryUnsafe()
-
-	// Create the aggregate error
-	aggregate := New("Multiple errors occurred", mostCommonCategory).
-		WithSeverity(highestSeverity).
-		WithMetadata(map[string]any{
-			"error_count":    len(c.errors),
-			"category_stats": categoryStats,
-			"severity_stats": severityStats,
-			"aggregated_at":  c.errors[0].err.Timestamp, // Use first error's timestamp
-		})
-
-	// Collect all validation errors
-	var allValidationErrors ValidationErrors
-	for _, collected := range c.errors {
-		allValidationErrors = append(allValidationErrors, collected.err.ValidationErrors...)
-	}
-	if len(allValidationErrors) > 0 {
-		aggregate.ValidationErrors = allValidationErrors
-	}
-
-	return aggregate
+	return c.mergeUnsafe()
 }
 
 // FilterBySeverity returns all errors with severity at or above the specified minimum
@@ -228,7 +204,7 @@ func (c *ErrorCollector) GetValidationErrors() ValidationErrors {
 
 	var allValidationErrors ValidationErrors
 	for _, collected := range c.errors {
-		allValidationErrors = append(allValidationErrors, collected.err.ValidationErrors...)
+		allValidationErrors = append(allValidationErrors, collected.validationErrors()...)
 	}
 	return allValidationErrors
 }
@@ -250,6 +226,23 @@ func (c *ErrorCollector) categoryStatsUnsafe() map[Category]int {
 	return stats
 }
 
+// CategoryStatsRollup returns the count of errors by category, each
+// error also counts toward every ancestor of its category
+func (c *ErrorCollector) CategoryStatsRollup() map[Category]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := make(map[Category]int)
+	for _, collected := range c.errors {
+		category := collected.err.Category
+		stats[category]++
+		for _, ancestor := range category.Ancestors() {
+			stats[ancestor]++
+		}
+	}
+	return stats
+}
+
 // MostCommonCategory returns the category with the highest error count
 func (c *ErrorCollector) MostCommonCategory() Category {
 	c.mu.RLock()
@@ -275,6 +268,37 @@ func (c *ErrorCollector) mostCommonCategoryUnsafe() Category {
 	return mostCommon
 }
 
+// FingerprintStats returns the count of errors by fingerprint, see Fingerprint
+func (c *ErrorCollector) FingerprintStats() map[string]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fingerprintStatsUnsafe()
+}
+
+// fingerprintStatsUnsafe returns fingerprint statistics without acquiring locks
+// Must be called while holding at least a read lock
+func (c *ErrorCollector) fingerprintStatsUnsafe() map[string]int {
+	stats := make(map[string]int)
+	for _, collected := range c.errors {
+		stats[collected.err.Fingerprint()]++
+	}
+	return stats
+}
+
+// GroupByFingerprint returns the collected errors grouped by fingerprint,
+// each group keeps the order in which errors were added
+func (c *ErrorCollector) GroupByFingerprint() map[string][]*Error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	groups := make(map[string][]*Error)
+	for _, collected := range c.errors {
+		fingerprint := collected.err.Fingerprint()
+		groups[fingerprint] = append(groups[fingerprint], collected.err)
+	}
+	return groups
+}
+
 // SeverityDistribution returns the count of errors by severity level
 func (c *ErrorCollector) SeverityDistribution() map[Severity]int {
 	c.mu.RLock()
@@ -320,6 +344,10 @@ func (c *ErrorCollector) GetAllValidationErrors() ValidationErrors {
 	var allValidationErrors ValidationErrors
 	for _, collected := range c.errors {
 		// Get all validation errors (including from wrapped errors)
+		if collected.multi != nil {
+			allValidationErrors = append(allValidationErrors, collected.multi.AllValidationErrors()...)
+			continue
+		}
 		allValidationErrors = append(allValidationErrors, collected.err.AllValidationErrors()...)
 	}
 	return allValidationErrors
@@ -368,6 +396,7 @@ func (c *ErrorCollector) GetRetryableErrors() []*Error {
 // If the collector has no errors, returns nil
 // If the collector has exactly one error, returns that error's response
 // If the collector has multiple errors, returns a merged error response
+// Both follow the response policy of the current Mode
 func (c *ErrorCollector) ToErrorResponse(includeStack bool) *ErrorResponse {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -378,7 +407,12 @@ func (c *ErrorCollector) ToErrorResponse(includeStack bool) *ErrorResponse {
 
 	if len(c.errors) == 1 {
 		// For single error, use its existing ToErrorResponse method
-		response := c.errors[0].err.ToErrorResponse(includeStack, c.errors[0].err.StackTrace)
+		collected := c.errors[0]
+		if collected.multi != nil {
+			response := collected.multi.ToErrorResponse(includeStack, collected.err.StackTrace)
+			return &response
+		}
+		response := collected.err.ToErrorResponse(includeStack, collected.err.StackTrace)
 		return &response
 	}
 
@@ -400,7 +434,11 @@ func (c *ErrorCollector) mergeUnsafe() *Error {
 	}
 
 	if len(c.errors) == 1 {
-		return c.errors[0].err.Clone()
+		merged := c.errors[0].err.Clone()
+		if c.errors[0].multi != nil {
+			merged.ValidationErrors = c.errors[0].validationErrors()
+		}
+		return merged
 	}
 
 	// Create aggregate error with metadata about collected errors
@@ -420,6 +458,7 @@ func (c *ErrorCollector) mergeUnsafe() *Error {
 
 	// Create the aggregate error
 	aggregate := New("Multiple errors occurred", mostCommonCategory).
+		WithPublicMessage("Multiple errors occurred").
 		WithSeverity(highestSeverity).
 		WithMetadata(map[string]any{
 			"error_count":    len(c.errors),
@@ -431,7 +470,7 @@ func (c *ErrorCollector) mergeUnsafe() *Error {
 	// Collect all validation errors
 	var allValidationErrors ValidationErrors
 	for _, collected := range c.errors {
-		allValidationErrors = append(allValidationErrors, collected.err.ValidationErrors...)
+		allValidationErrors = append(allValidationErrors, collected.validationErrors()...)
 	}
 	if len(allValidationErrors) > 0 {
 		aggregate.ValidationErrors = allValidationErrors
@@ -466,10 +505,13 @@ func (c *ErrorCollector) ToSlogAttributes() []slog.Attr {
 		mostCommon := c.mostCommonCategoryUnsafe()
 		attrs = append(attrs, slog.String("most_common_category", mostCommon.String()))
 
+		// Distinct error classes
+		attrs = append(attrs, slog.Int("unique_fingerprints", len(c.fingerprintStatsUnsafe())))
+
 		// Validation error count
 		var allValidationErrors ValidationErrors
 		for _, collected := range c.errors {
-			allValidationErrors = append(allValidationErrors, collected.err.ValidationErrors...)
+			allValidationErrors = append(allValidationErrors, collected.validationErrors()...)
 		}
 		if len(allValidationErrors) > 0 {
 			attrs = append(attrs, slog.Int("validation_error_count", len(allValidationErrors)))
@@ -505,6 +547,7 @@ func (c *ErrorCollector) LogErrors(logger *slog.Logger) {
 	for i, err := range errors {
 		// Create combined attributes with both error and collector information
 		errorAttrs := ToSlogAttributes(err)
+		err = redactionPolicy.Log.Redact(err)
 		allAttrs := make([]slog.Attr, 0, len(errorAttrs)+len(collectorAttrs)+1)
 		allAttrs = append(allAttrs, errorAttrs...)
 		allAttrs = append(allAttrs, collectorAttrs...)
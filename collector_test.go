@@ -287,6 +287,31 @@ func TestErrorCollector_CategoryAnalysis(t *testing.T) {
 	}
 }
 
+func TestErrorCollector_CategoryStatsRollup(t *testing.T) {
+	c := NewCollector()
+
+	c.Add(New("error 1", CategoryValidation))
+	c.Add(New("error 2", CategoryValidation.Extend("email")))
+	c.Add(New("error 3", CategoryValidation.Extend("email").Extend("format")))
+	c.Add(New("error 4", CategoryInternal))
+
+	stats := c.CategoryStats()
+	if stats[CategoryValidation] != 1 {
+		t.Errorf("CategoryStats[Validation] = %d, want 1", stats[CategoryValidation])
+	}
+
+	rollup := c.CategoryStatsRollup()
+	if rollup[CategoryValidation] != 3 {
+		t.Errorf("CategoryStatsRollup[Validation] = %d, want 3", rollup[CategoryValidation])
+	}
+	if rollup[CategoryValidation.Extend("email")] != 1 {
+		t.Errorf("CategoryStatsRollup[validation_email] = %d, want 1", rollup[CategoryValidation.Extend("email")])
+	}
+	if rollup[CategoryInternal] != 1 {
+		t.Errorf("CategoryStatsRollup[Internal] = %d, want 1", rollup[CategoryInternal])
+	}
+}
+
 func TestErrorCollector_ValidationErrors(t *testing.T) {
 	c := NewCollector()
 
@@ -315,6 +340,34 @@ func TestErrorCollector_ValidationErrors(t *testing.T) {
 	}
 }
 
+func TestErrorCollector_MultiErrorValidation(t *testing.T) {
+	c := NewCollector()
+
+	multi := &MultiError{
+		BaseError: New("signup failed", CategoryValidation),
+		Causes: []error{
+			NewValidation("invalid", FieldError{Field: "email", Message: "required"}),
+			NewValidation("invalid", FieldError{Field: "name", Message: "required"}),
+		},
+	}
+	c.Add(multi)
+
+	if got := c.GetValidationErrors(); len(got) != 2 {
+		t.Errorf("GetValidationErrors() = %d errors, want 2", len(got))
+	}
+	if merged := c.Merge(); len(merged.ValidationErrors) != 2 {
+		t.Errorf("Merge() kept %d validation errors, want 2", len(merged.ValidationErrors))
+	}
+	if response := c.ToErrorResponse(false); len(response.Error.ValidationErrors) != 2 {
+		t.Errorf("ToErrorResponse() kept %d validation errors, want 2", len(response.Error.ValidationErrors))
+	}
+
+	c.Add(NewValidation("invalid", FieldError{Field: "age", Message: "too low"}))
+	if merged := c.Merge(); len(merged.ValidationErrors) != 3 {
+		t.Errorf("Merge() kept %d validation errors, want 3", len(merged.ValidationErrors))
+	}
+}
+
 func TestErrorCollector_RetryableErrors(t *testing.T) {
 	c := NewCollector()
 
@@ -368,7 +421,7 @@ func TestErrorCollector_HttpResponse(t *testing.T) {
 		},
 		{
 			name:       "single error",
-			errors:     []*Error{New("single error")},
+			errors:     []*Error{New("internal detail").WithPublicMessage("single error")},
 			wantSingle: true,
 		},
 		{
@@ -531,3 +584,56 @@ func BenchmarkErrorCollector_CategoryStats(b *testing.B) {
 		c.CategoryStats()
 	}
 }
+
+func TestErrorCollector_MultiError(t *testing.T) {
+	collector := NewCollector()
+
+	multi := WrapAll(CategoryOperation, "batch failed",
+		NewValidation("invalid", FieldError{Field: "email", Message: "required"}),
+		NewValidation("invalid", FieldError{Field: "name", Message: "required"}),
+	)
+	collector.Add(multi)
+
+	errs := collector.Errors()
+	if len(errs) != 1 || errs[0].Category != CategoryOperation {
+		t.Fatalf("Expected the MultiError base to be collected, got %v", errs)
+	}
+
+	if got := collector.GetAllValidationErrors(); len(got) != 2 {
+		t.Errorf("Expected validation errors from every cause, got %v", got)
+	}
+}
+
+func TestErrorCollector_Fingerprints(t *testing.T) {
+	collector := NewCollector()
+	location := &ErrorLocation{Function: "orders.Load"}
+
+	for i := range 3 {
+		collector.Add(New(fmt.Sprintf("order %d not found", i), CategoryNotFound).WithLocation(location))
+	}
+	collector.Add(New("payment declined", CategoryOperation).WithLocation(location))
+
+	stats := collector.FingerprintStats()
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 fingerprints, got %d", len(stats))
+	}
+
+	groups := collector.GroupByFingerprint()
+	notFound := groups[collector.Errors()[0].Fingerprint()]
+	if len(notFound) != 3 || stats[notFound[0].Fingerprint()] != 3 {
+		t.Errorf("Expected 3 grouped not found errors, got %d", len(notFound))
+	}
+	if notFound[2].Message != "order 2 not found" {
+		t.Errorf("Expected groups to keep insertion order, got %s", notFound[2].Message)
+	}
+
+	found := false
+	for _, attr := range collector.ToSlogAttributes() {
+		if attr.Key == "unique_fingerprints" {
+			found = attr.Value.Int64() == 2
+		}
+	}
+	if !found {
+		t.Error("Expected unique_fingerprints in collector attributes")
+	}
+}
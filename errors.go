@@ -13,7 +13,8 @@ import (
 
 // Global behavior
 var (
-	Verbose       = false
+	Verbose = false
+	// IsDevelopment selects the development response policy, see SetMode
 	IsDevelopment = false
 )
 
@@ -25,10 +26,12 @@ var (
 )
 
 type Error struct {
-	Category         Category         `json:"category"`
-	Code             int              `json:"code,omitempty"`
-	TextCode         string           `json:"text_code,omitempty"`
-	Message          string           `json:"message"`
+	Category Category `json:"category"`
+	Code     int      `json:"code,omitempty"`
+	TextCode string   `json:"text_code,omitempty"`
+	Message  string   `json:"message"`
+	// PublicMessage is the text shown to API clients, Message stays internal
+	PublicMessage    string           `json:"public_message,omitempty"`
 	Source           error            `json:"-"`
 	ValidationErrors ValidationErrors `json:"validation_errors,omitempty"`
 	Metadata         map[string]any   `json:"metadata,omitempty"`
@@ -37,6 +40,14 @@ type Error struct {
 	StackTrace       StackTrace       `json:"stack_trace,omitempty"`
 	Location         *ErrorLocation   `json:"location,omitempty"`
 	Severity         Severity         `json:"severity"`
+
+	// kind is the definition the error was created from, if any
+	kind *Kind
+	// frozen makes fluent methods return a modified clone, see Freeze
+	frozen bool
+	// originFingerprint is the fingerprint of the error a redacted or
+	// stripped copy was made from, so outputs match the logs
+	originFingerprint string
 }
 
 func (e *Error) Error() string {
@@ -79,7 +90,28 @@ func (e *Error) Unwrap() error {
 	return e.Source
 }
 
+// Is reports whether e matches target. A target *Error with a TextCode
+// matches errors with the same TextCode, otherwise it matches errors of
+// the same Category or one of its sub-categories. This lets sentinels
+// match after Wrap or Clone.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || t == nil || e == nil {
+		return false
+	}
+
+	if t.TextCode != "" {
+		return e.TextCode == t.TextCode
+	}
+
+	return e.Category.IsA(t.Category)
+}
+
 func (e *Error) WithMetadata(metas ...map[string]any) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	if e.Metadata == nil {
 		e.Metadata = make(map[string]any)
 	}
@@ -93,27 +125,66 @@ func (e *Error) WithMetadata(metas ...map[string]any) *Error {
 
 // TODO: either remove or rename to WithTraceID
 func (e *Error) WithRequestID(id string) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.RequestID = id
 	return e
 }
 
 func (e *Error) WithStackTrace() *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.StackTrace = CaptureStackTrace(1)
 	return e
 }
 
 func (e *Error) WithCode(code int) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.Code = code
 	return e
 }
 
 func (e *Error) WithTextCode(code string) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.TextCode = code
 	return e
 }
 
+// WithPublicMessage sets the message shown to API clients
+func (e *Error) WithPublicMessage(message string) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
+	e.PublicMessage = message
+	return e
+}
+
+// GetPublicMessage returns the message shown to API clients, falling
+// back to the generic message of the error category
+func (e *Error) GetPublicMessage() string {
+	if e.PublicMessage != "" {
+		return e.PublicMessage
+	}
+	return CategoryPublicMessage(e.Category)
+}
+
 // WithLocation sets the location where the error occurred
 func (e *Error) WithLocation(loc *ErrorLocation) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.Location = loc
 	return e
 }
@@ -130,6 +201,10 @@ func (e *Error) HasLocation() bool {
 
 // WithSeverity sets the severity level of the error
 func (e *Error) WithSeverity(s Severity) *Error {
+	if e == nil {
+		return nil
+	}
+	e = e.mutable()
 	e.Severity = s
 	return e
 }
@@ -152,116 +227,298 @@ func (e *Error) IsAboveSeverity(threshold Severity) bool {
 // ValidationMap returns validation errors as a map
 // for easy template usage
 func (e *Error) ValidationMap() map[string]string {
-	return e.allValidationMapWithPath("")
+	return fieldMessages(e.validationFieldsWithPath(""))
 }
 
+// AllValidationErrors returns the validation errors of the error and of
+// every branch of its source tree
 func (e *Error) AllValidationErrors() ValidationErrors {
 	var allErrors ValidationErrors
 	allErrors = append(allErrors, e.ValidationErrors...)
-	if len(e.ValidationErrors) == 0 && e.Source != nil {
-		if validationErrors, ok := e.Source.(validation.Errors); ok {
-			for field, fieldErr := range validationErrors {
-				allErrors = append(allErrors, FieldError{
-					Field:   field,
-					Message: strings.TrimSpace(fieldErr.Error()),
-				})
-			}
-		}
+
+	if e.Source == nil {
+		return allErrors
 	}
 
-	if e.Source != nil {
-		if soureErr, ok := e.Source.(*Error); ok {
-			allErrors = append(allErrors, soureErr.AllValidationErrors()...)
+	if validationErrors, ok := e.Source.(validation.Errors); ok {
+		if len(e.ValidationErrors) == 0 {
+			allErrors = append(allErrors, fromOzzoFieldErrors(validationErrors)...)
 		}
+		return allErrors
 	}
 
-	return allErrors
+	// Wrap copies the validation errors of a nested *Error, skip the
+	// ones we already hold when they show up again in the source tree
+	return appendUniqueFieldErrors(allErrors, collectValidationErrors(e.Source)...)
 }
 
-func (e *Error) allValidationMapWithPath(prefix string) map[string]string {
-	result := make(map[string]string)
+// validationFieldsWithPath returns the field errors of the error tree
+// keyed by their path, see ValidationMap
+func (e *Error) validationFieldsWithPath(prefix string) map[string]FieldError {
+	result := make(map[string]FieldError)
 
 	for _, fieldErr := range e.ValidationErrors {
-		key := fieldErr.Field
-		if prefix != "" {
-			key = prefix + "." + fieldErr.Field
-		}
-		result[key] = fieldErr.Message
+		result[prefixKey(prefix, fieldErr.Field)] = fieldErr
 	}
 
-	if len(e.ValidationErrors) == 0 && e.Source != nil {
-		if validationErrors, ok := e.Source.(validation.Errors); ok {
-			for field, fieldErr := range validationErrors {
-				key := field
-				if prefix != "" {
-					key = prefix + "." + field
-				}
-				result[key] = strings.TrimSpace(fieldErr.Error())
+	if e.Source == nil {
+		return result
+	}
+
+	if validationErrors, ok := e.Source.(validation.Errors); ok {
+		if len(e.ValidationErrors) == 0 {
+			for _, fieldErr := range fromOzzoFieldErrors(validationErrors) {
+				result[prefixKey(prefix, fieldErr.Field)] = fieldErr
 			}
 		}
+		return result
+	}
+
+	for k, v := range validationFieldsOf(e.Source, prefixKey(prefix, "source")) {
+		result[k] = v
+	}
+
+	return result
+}
+
+// errorJSON is the wire representation shared by MarshalJSON and UnmarshalJSON
+type errorJSON struct {
+	Category         Category          `json:"category"`
+	Code             int               `json:"code,omitempty"`
+	TextCode         string            `json:"text_code,omitempty"`
+	Message          string            `json:"message"`
+	PublicMessage    string            `json:"public_message,omitempty"`
+	Source           string            `json:"source,omitempty"`
+	Causes           []json.RawMessage `json:"causes,omitempty"`
+	ValidationErrors ValidationErrors  `json:"validation_errors,omitempty"`
+	Metadata         map[string]any    `json:"metadata,omitempty"`
+	RequestID        string            `json:"request_id,omitempty"`
+	Timestamp        string            `json:"timestamp"`
+	StackTrace       StackTrace        `json:"stack_trace,omitempty"`
+	Location         *ErrorLocation    `json:"location,omitempty"`
+	Severity         Severity          `json:"severity"`
+	Fingerprint      string            `json:"fingerprint,omitempty"`
+	// Retryable and RetryDelay are only set for a *RetryableError
+	Retryable  *bool  `json:"retryable,omitempty"`
+	RetryDelay string `json:"retry_delay,omitempty"`
+}
+
+// MarshalJSON implements JSON marshaling for Error.
+// Nested *Error sources, and every branch of a multi cause source, are
+// serialized as a structured causes array. Any other source is
+// flattened to its text. The JSON redactor of the RedactionPolicy is
+// applied first.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return redactionPolicy.JSON.Redact(e).marshalJSON()
+}
+
+// marshalJSON encodes the error as is, the redaction policy is applied
+// once to the whole tree by MarshalJSON
+func (e *Error) marshalJSON() ([]byte, error) {
+	aux, err := e.toJSONTree()
+	if err != nil {
+		return nil, err
 	}
+	return json.Marshal(aux)
+}
+
+// toJSONTree is toJSON with the source encoded as causes, or as its text
+// for foreign errors
+func (e *Error) toJSONTree() (errorJSON, error) {
+	aux := e.toJSON()
 
 	if e.Source != nil {
-		if sourceErr, ok := e.Source.(*Error); ok {
-			newPrefix := prefix
-			if newPrefix == "" {
-				newPrefix = "source"
-			} else {
-				newPrefix = prefix + ".source"
+		switch source := e.Source.(type) {
+		case *Error, *MultiError, *RetryableError:
+			causes, err := encodeCauses([]error{source})
+			if err != nil {
+				return aux, err
 			}
-
-			for k, v := range sourceErr.allValidationMapWithPath(newPrefix) {
-				result[k] = v
+			aux.Causes = causes
+		case interface{ Unwrap() []error }:
+			causes, err := encodeCauses(source.Unwrap())
+			if err != nil {
+				return aux, err
 			}
+			aux.Causes = causes
+		default:
+			aux.Source = source.Error()
 		}
 	}
 
-	return result
+	return aux, nil
 }
 
-func (e *Error) MarshalJSON() ([]byte, error) {
-	type alias struct {
-		Category         Category         `json:"category"`
-		Code             int              `json:"code,omitempty"`
-		TextCode         string           `json:"text_code,omitempty"`
-		Message          string           `json:"message"`
-		Source           string           `json:"source,omitempty"`
-		ValidationErrors ValidationErrors `json:"validation_errors,omitempty"`
-		Metadata         map[string]any   `json:"metadata,omitempty"`
-		RequestID        string           `json:"request_id,omitempty"`
-		Timestamp        string           `json:"timestamp"`
-		StackTrace       StackTrace       `json:"stack_trace,omitempty"`
-		Location         *ErrorLocation   `json:"location,omitempty"`
-		Severity         Severity         `json:"severity"`
-	}
-
-	aux := alias{
+// UnmarshalJSON implements JSON unmarshaling for Error.
+// Structured causes are restored as *Error sources, several causes are
+// joined, and a plain source string is restored as an opaque error that
+// keeps its text.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var aux errorJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	causes, err := e.fromJSON(aux)
+	if err != nil {
+		return err
+	}
+
+	switch len(causes) {
+	case 0:
+	case 1:
+		e.Source = causes[0]
+	default:
+		e.Source = Join(causes...)
+	}
+
+	return nil
+}
+
+func (e *Error) toJSON() errorJSON {
+	return errorJSON{
 		Category:         e.Category,
 		Code:             e.Code,
 		TextCode:         e.TextCode,
 		Message:          e.Message,
+		PublicMessage:    e.PublicMessage,
 		ValidationErrors: e.ValidationErrors,
 		Metadata:         e.Metadata,
 		RequestID:        e.RequestID,
-		Timestamp:        e.Timestamp.Format(time.RFC3339),
+		Timestamp:        e.Timestamp.Format(time.RFC3339Nano),
 		StackTrace:       e.StackTrace,
 		Location:         e.Location,
 		Severity:         e.Severity,
+		Fingerprint:      e.Fingerprint(),
 	}
+}
 
-	if e.Source != nil {
-		aux.Source = e.Source.Error()
+// fromJSON populates every field but Source and returns the decoded causes
+func (e *Error) fromJSON(aux errorJSON) ([]error, error) {
+	var timestamp time.Time
+	if aux.Timestamp != "" {
+		t, err := time.Parse(time.RFC3339Nano, aux.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp: %w", err)
+		}
+		timestamp = t
 	}
 
-	return json.Marshal(aux)
+	*e = Error{
+		Category:         aux.Category,
+		Code:             aux.Code,
+		TextCode:         aux.TextCode,
+		Message:          aux.Message,
+		PublicMessage:    aux.PublicMessage,
+		ValidationErrors: aux.ValidationErrors,
+		Metadata:         aux.Metadata,
+		RequestID:        aux.RequestID,
+		Timestamp:        timestamp,
+		StackTrace:       aux.StackTrace,
+		Location:         aux.Location,
+		Severity:         aux.Severity,
+	}
+
+	causes, err := decodeCauses(aux.Causes)
+	if err != nil {
+		return nil, err
+	}
+
+	if aux.Source != "" {
+		causes = append(causes, &opaqueError{msg: aux.Source})
+	}
+
+	return causes, nil
 }
 
+// encodeCauses serializes rich errors as objects and foreign errors as strings
+func encodeCauses(errs []error) ([]json.RawMessage, error) {
+	var causes []json.RawMessage
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		var raw []byte
+		var marshalErr error
+		switch cause := err.(type) {
+		case *Error:
+			raw, marshalErr = cause.marshalJSON()
+		case *MultiError:
+			raw, marshalErr = cause.marshalJSON()
+		case *RetryableError:
+			raw, marshalErr = cause.marshalJSON()
+		default:
+			raw, marshalErr = json.Marshal(cause.Error())
+		}
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		causes = append(causes, raw)
+	}
+	return causes, nil
+}
+
+// decodeCauses restores causes produced by encodeCauses. Objects with a
+// retryable flag come back as *RetryableError, objects holding several
+// causes as *MultiError, other objects as *Error and strings as opaque
+// errors.
+func decodeCauses(raws []json.RawMessage) ([]error, error) {
+	var causes []error
+	for _, raw := range raws {
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			causes = append(causes, &opaqueError{msg: text})
+			continue
+		}
+
+		var aux errorJSON
+		if err := json.Unmarshal(raw, &aux); err != nil {
+			return nil, err
+		}
+
+		if aux.Retryable != nil {
+			retryErr := &RetryableError{}
+			if err := retryErr.fromJSON(aux); err != nil {
+				return nil, err
+			}
+			causes = append(causes, retryErr)
+			continue
+		}
+
+		base := &Error{}
+		nested, err := base.fromJSON(aux)
+		if err != nil {
+			return nil, err
+		}
+
+		switch len(nested) {
+		case 0:
+			causes = append(causes, base)
+		case 1:
+			base.Source = nested[0]
+			causes = append(causes, base)
+		default:
+			causes = append(causes, &MultiError{BaseError: base, Causes: nested})
+		}
+	}
+	return causes, nil
+}
+
+// opaqueError stands in for a foreign source error restored from JSON
+type opaqueError struct {
+	msg string
+}
+
+func (o *opaqueError) Error() string { return o.msg }
+
 func (e *Error) Clone() *Error {
 	if e == nil {
 		return nil
 	}
 
 	clone := *e // shallow copy
+	clone.frozen = false
 
 	if e.ValidationErrors != nil {
 		clone.ValidationErrors = make(ValidationErrors, len(e.ValidationErrors))
@@ -286,6 +543,16 @@ func (e *Error) Clone() *Error {
 	return &clone
 }
 
+// outputClone is Clone for copies about to be redacted or stripped, the
+// copy keeps the fingerprint of e
+func (e *Error) outputClone() *Error {
+	clone := e.Clone()
+	if clone != nil {
+		clone.originFingerprint = e.Fingerprint()
+	}
+	return clone
+}
+
 // New creates a new Error with the specified category and message
 func New(message string, category ...Category) *Error {
 	cat := CategoryInternal
@@ -297,7 +564,7 @@ func New(message string, category ...Category) *Error {
 		Message:   message,
 		Timestamp: time.Now(),
 		Location:  captureLocation(1), // Capture caller's location
-		Severity:  SeverityError,      // Default severity
+		Severity:  categorySeverity(cat),
 	}
 }
 
@@ -323,7 +590,7 @@ func Wrap(source error, category Category, message string) *Error {
 		Source:    source,
 		Timestamp: time.Now(),
 		Location:  captureLocation(1), // Capture new location for non-Error sources
-		Severity:  SeverityError,      // Default severity
+		Severity:  categorySeverity(category),
 	}
 }
 
@@ -334,10 +601,15 @@ func NewWithLocation(message string, category Category, location *ErrorLocation)
 		Message:   message,
 		Timestamp: time.Now(),
 		Location:  location,
-		Severity:  SeverityError, // Default severity
+		Severity:  categorySeverity(category),
 	}
 }
 
+// categorySeverity returns the default severity of the category
+func categorySeverity(category Category) Severity {
+	return DefaultCategoryRegistry.Definition(category).Severity
+}
+
 // NewCritical creates a new Error with Critical severity
 func NewCritical(message string, category Category) *Error {
 	return New(message, category).WithSeverity(SeverityCritical)
@@ -370,19 +642,13 @@ func IsWrapped(err error) bool {
 	return As(err, &customErr) || As(err, &retryableErr)
 }
 
+// RootCause returns the deepest error in the chain. When an error has
+// several causes the first branch is followed
 func RootCause(err error) error {
-	for {
-		unwrapped := Unwrap(err)
-		if unwrapped == nil {
-			return err
-		}
-		err = unwrapped
-	}
-}
-
-func RootCategory(err error) Category {
-	if rootErr, ok := RootCause(err).(*Error); ok {
-		return rootErr.Category
-	}
-	return CategoryInternal
+	root := err
+	Walk(err, func(node error, _ int) bool {
+		root = node
+		return len(unwrapAll(node)) > 0
+	})
+	return root
 }
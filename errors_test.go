@@ -247,6 +247,143 @@ func TestError_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestError_UnmarshalJSON_RoundTrip(t *testing.T) {
+	inner := errors.New("record missing", errors.CategoryNotFound).
+		WithCode(404).
+		WithTextCode("RECORD_NOT_FOUND").
+		WithSeverity(errors.SeverityWarning)
+	inner.Source = fmt.Errorf("sql: no rows in result set")
+
+	outer := &errors.Error{
+		Category:  errors.CategoryInternal,
+		Message:   "lookup failed",
+		Source:    inner,
+		RequestID: "req-123",
+		Timestamp: time.Date(2023, 1, 1, 12, 0, 0, 123456789, time.UTC),
+		ValidationErrors: errors.ValidationErrors{
+			{Field: "email", Message: "required"},
+		},
+		Metadata: map[string]any{"key": "value"},
+		Severity: errors.SeverityCritical,
+	}
+
+	data, err := json.Marshal(outer)
+	if err != nil {
+		t.Fatalf("Failed to marshal error: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Failed to unmarshal raw: %v", err)
+	}
+	if _, ok := raw["source"]; ok {
+		t.Errorf("Expected nested *Error source to be serialized as causes, got source=%v", raw["source"])
+	}
+	if causes, ok := raw["causes"].([]any); !ok || len(causes) != 1 {
+		t.Fatalf("Expected a single structured cause, got %v", raw["causes"])
+	}
+
+	var decoded errors.Error
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal error: %v", err)
+	}
+
+	if decoded.Category != outer.Category || decoded.Message != outer.Message || decoded.RequestID != outer.RequestID {
+		t.Errorf("Expected top level fields to round-trip, got %+v", decoded)
+	}
+	if !decoded.Timestamp.Equal(outer.Timestamp) {
+		t.Errorf("Expected timestamp %v, got %v", outer.Timestamp, decoded.Timestamp)
+	}
+	if decoded.Severity != errors.SeverityCritical {
+		t.Errorf("Expected severity CRITICAL, got %v", decoded.Severity)
+	}
+	if len(decoded.ValidationErrors) != 1 || decoded.ValidationErrors[0].Field != "email" {
+		t.Errorf("Expected validation errors to round-trip, got %v", decoded.ValidationErrors)
+	}
+	if decoded.Metadata["key"] != "value" {
+		t.Errorf("Expected metadata to round-trip, got %v", decoded.Metadata)
+	}
+
+	cause, ok := decoded.Source.(*errors.Error)
+	if !ok {
+		t.Fatalf("Expected source to be *errors.Error, got %T", decoded.Source)
+	}
+	if cause.TextCode != "RECORD_NOT_FOUND" || cause.Code != 404 || cause.Severity != errors.SeverityWarning {
+		t.Errorf("Expected nested cause to round-trip, got %+v", cause)
+	}
+	if cause.Location == nil || cause.Location.Line != inner.Location.Line {
+		t.Errorf("Expected nested cause location to round-trip, got %v", cause.Location)
+	}
+	if !errors.IsNotFound(decoded.Source) {
+		t.Error("Expected nested cause category to be preserved")
+	}
+
+	if cause.Source == nil || cause.Source.Error() != "sql: no rows in result set" {
+		t.Errorf("Expected foreign source text to be preserved, got %v", cause.Source)
+	}
+	if _, isRich := cause.Source.(*errors.Error); isRich {
+		t.Error("Expected foreign source to be restored as an opaque error")
+	}
+}
+
+func TestError_UnmarshalJSON_RetryableSource(t *testing.T) {
+	retryErr := errors.NewRetryable("upstream busy", errors.CategoryExternal).
+		WithCode(503).
+		WithTextCode("SERVICE_UNAVAILABLE").
+		WithRetryDelay(2 * time.Second)
+	outer := &errors.Error{
+		Category:  errors.CategoryHandler,
+		Message:   "sync failed",
+		Source:    retryErr,
+		Timestamp: time.Now(),
+		Severity:  errors.SeverityError,
+	}
+
+	data, err := json.Marshal(outer)
+	if err != nil {
+		t.Fatalf("Failed to marshal error: %v", err)
+	}
+
+	var decoded errors.Error
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal error: %v", err)
+	}
+
+	cause, ok := decoded.Source.(*errors.RetryableError)
+	if !ok {
+		t.Fatalf("Expected source to be *errors.RetryableError, got %T", decoded.Source)
+	}
+	if cause.Category != errors.CategoryExternal || cause.Code != 503 || cause.TextCode != "SERVICE_UNAVAILABLE" {
+		t.Errorf("Expected the retryable error fields to round-trip, got %+v", cause.BaseError)
+	}
+	if !cause.IsRetryable() || cause.RetryDelay(0) != 2*time.Second {
+		t.Errorf("Expected retryable with a 2s delay, got %v %v", cause.IsRetryable(), cause.RetryDelay(0))
+	}
+	if !errors.IsRetryableError(&decoded) {
+		t.Error("Expected the decoded tree to stay retryable")
+	}
+
+	data, err = json.Marshal(retryErr.WithRetryable(false))
+	if err != nil {
+		t.Fatalf("Failed to marshal retryable error: %v", err)
+	}
+	var top errors.RetryableError
+	if err := json.Unmarshal(data, &top); err != nil {
+		t.Fatalf("Failed to unmarshal retryable error: %v", err)
+	}
+	if top.IsRetryable() || top.Message != "upstream busy" {
+		t.Errorf("Expected a non retryable error to round-trip, got %v %q", top.IsRetryable(), top.Message)
+	}
+}
+
+func TestError_UnmarshalJSON_InvalidTimestamp(t *testing.T) {
+	var decoded errors.Error
+	err := json.Unmarshal([]byte(`{"category":"internal","message":"x","timestamp":"yesterday","severity":"ERROR"}`), &decoded)
+	if err == nil {
+		t.Fatal("Expected error for invalid timestamp")
+	}
+}
+
 func TestNew(t *testing.T) {
 	category := errors.CategoryNotFound
 	message := "resource not found"
@@ -595,3 +732,143 @@ func BenchmarkError_ValidationMap(b *testing.B) {
 		_ = err.ValidationMap()
 	}
 }
+
+func TestError_Is(t *testing.T) {
+	errUserNotFound := errors.New("user not found", errors.CategoryNotFound).WithTextCode("USER_NOT_FOUND")
+	errOrderNotFound := errors.New("order not found", errors.CategoryNotFound).WithTextCode("ORDER_NOT_FOUND")
+
+	wrapped := errors.Wrap(errUserNotFound, errors.CategoryInternal, "lookup failed")
+	cloned := errUserNotFound.Clone()
+	foreignWrapped := fmt.Errorf("handler: %w", cloned)
+
+	tests := []struct {
+		name     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{"same pointer", errUserNotFound, errUserNotFound, true},
+		{"clone matches text code", cloned, errUserNotFound, true},
+		{"wrapped matches text code", wrapped, errUserNotFound, true},
+		{"foreign wrapper matches text code", foreignWrapped, errUserNotFound, true},
+		{"different text code", errOrderNotFound, errUserNotFound, false},
+		{"category sentinel", errOrderNotFound, errors.ErrNotFound, true},
+		{"category sentinel through wrapper", foreignWrapped, errors.ErrNotFound, true},
+		{"different category sentinel", errOrderNotFound, errors.ErrValidation, false},
+		{"text code target ignores category only errors", errors.New("x", errors.CategoryNotFound), errUserNotFound, false},
+		{"foreign target", errUserNotFound, fmt.Errorf("user not found"), false},
+		{"retryable matches sentinel", errors.NewRetryable("timeout", errors.CategoryExternal), errors.ErrExternal, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stdErrors.Is(tt.err, tt.target); got != tt.expected {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestError_PublicMessage(t *testing.T) {
+	err := errors.New("pq: duplicate key value violates unique constraint \"users_email_key\"", errors.CategoryConflict).
+		WithPublicMessage("The email is already registered")
+
+	if err.GetPublicMessage() != "The email is already registered" {
+		t.Errorf("Expected public message, got %q", err.GetPublicMessage())
+	}
+	if !strings.Contains(err.Error(), "users_email_key") {
+		t.Error("Expected Error() to keep the internal message")
+	}
+
+	response := err.ToErrorResponse(false, nil)
+	if response.Error.Message != "The email is already registered" || response.Error.PublicMessage != "" {
+		t.Errorf("Expected response to only carry the public message, got %q / %q",
+			response.Error.Message, response.Error.PublicMessage)
+	}
+	if !strings.Contains(err.Message, "users_email_key") {
+		t.Error("Expected ToErrorResponse to leave the original message untouched")
+	}
+
+	data, _ := json.Marshal(response)
+	if strings.Contains(string(data), "users_email_key") {
+		t.Errorf("Expected internal message to stay out of the response, got %s", data)
+	}
+
+	// JSON for logs keeps both
+	data, _ = json.Marshal(err)
+	var decoded errors.Error
+	if jsonErr := json.Unmarshal(data, &decoded); jsonErr != nil {
+		t.Fatalf("Unexpected error: %v", jsonErr)
+	}
+	if decoded.PublicMessage != err.PublicMessage || decoded.Message != err.Message {
+		t.Error("Expected JSON round trip to keep both messages")
+	}
+}
+
+func TestError_PublicMessageFallback(t *testing.T) {
+	err := errors.New("row 42 missing in orders", errors.CategoryNotFound)
+
+	if got := err.ToErrorResponse(false, nil).Error.Message; got != errors.CategoryPublicMessage(errors.CategoryNotFound) {
+		t.Errorf("Expected category generic message, got %q", got)
+	}
+
+	custom := errors.Category("billing")
+	if errors.CategoryPublicMessage(custom) != errors.DefaultPublicMessage {
+		t.Error("Expected DefaultPublicMessage for categories without a message")
+	}
+
+	errors.SetCategoryPublicMessage(custom, "Billing is unavailable")
+	t.Cleanup(func() { errors.DefaultCategoryRegistry.Unregister(custom) })
+	if got := errors.New("stripe: card_declined", custom).GetPublicMessage(); got != "Billing is unavailable" {
+		t.Errorf("Expected custom category message, got %q", got)
+	}
+}
+
+func TestToErrorResponse_PublicMessageInCauses(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+	original := errors.GetResponsePolicy(errors.ModeProduction)
+	t.Cleanup(func() { errors.SetResponsePolicy(errors.ModeProduction, original) })
+
+	policy := errors.ProductionResponsePolicy()
+	policy.Client.ExposeSource = true
+	errors.SetResponsePolicy(errors.ModeProduction, policy)
+
+	inner := errors.New("pq: duplicate key users_email_key secret@x.com", errors.CategoryConflict).
+		WithPublicMessage("The email is already registered")
+	err := &errors.Error{
+		Category: errors.CategoryConflict,
+		Code:     409,
+		Message:  "create user",
+		Source:   fmt.Errorf("insert: %w", errors.Join(inner, fmt.Errorf("tx aborted"))),
+	}
+
+	data, jsonErr := json.Marshal(err.ToErrorResponse(false, nil))
+	if jsonErr != nil {
+		t.Fatalf("Unexpected error: %v", jsonErr)
+	}
+	body := string(data)
+	if strings.Contains(body, "secret@x.com") || strings.Contains(body, "tx aborted") || strings.Contains(body, "insert") {
+		t.Errorf("Expected internal messages of causes to be stripped, got %s", body)
+	}
+	if !strings.Contains(body, "The email is already registered") {
+		t.Errorf("Expected the cause public message, got %s", body)
+	}
+	if inner.Message != "pq: duplicate key users_email_key secret@x.com" {
+		t.Error("Expected the original cause to be unchanged")
+	}
+}
+
+func TestMapAuthErrors_PublicMessage(t *testing.T) {
+	mapped := errors.MapAuthErrors(fmt.Errorf("jwt: token expired for user jane@example.com"))
+	if mapped == nil {
+		t.Fatal("Expected auth error to be mapped")
+	}
+
+	response := mapped.ToErrorResponse(false, nil)
+	if response.Error.Message != "The token has expired" {
+		t.Errorf("Expected kind public message, got %q", response.Error.Message)
+	}
+	if !strings.Contains(mapped.Error(), "jane@example.com") {
+		t.Error("Expected the mapped error to keep the original text internally")
+	}
+}
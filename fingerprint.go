@@ -0,0 +1,133 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FingerprintOptions selects the components hashed by Fingerprint
+type FingerprintOptions struct {
+	// Category includes the error category
+	Category bool
+	// TextCode includes the text code
+	TextCode bool
+	// Message includes the message normalized with NormalizeMessage
+	Message bool
+	// Location includes the function where the error was created
+	Location bool
+	// StackFrames includes the function names of the top N stack frames,
+	// 0 disables it. Files and lines are left out so the fingerprint
+	// survives unrelated code changes
+	StackFrames int
+}
+
+// DefaultFingerprintOptions hashes category, text code, normalized message
+// and creation location
+var DefaultFingerprintOptions = FingerprintOptions{
+	Category: true,
+	TextCode: true,
+	Message:  true,
+	Location: true,
+}
+
+// fingerprintOptions is used by Fingerprint, JSON output and slog attributes
+var fingerprintOptions = DefaultFingerprintOptions
+
+// SetFingerprintOptions changes the components used by Fingerprint.
+// Meant to be called during startup
+func SetFingerprintOptions(opts FingerprintOptions) {
+	fingerprintOptions = opts
+}
+
+// GetFingerprintOptions returns the components used by Fingerprint
+func GetFingerprintOptions() FingerprintOptions {
+	return fingerprintOptions
+}
+
+// Fingerprint returns a stable hash identifying the class of err, so
+// occurrences of the same error can be grouped. It uses the options set
+// with SetFingerprintOptions. Foreign errors are fingerprinted by their
+// normalized message. Returns an empty string for nil
+func Fingerprint(err error) string {
+	return FingerprintWith(err, fingerprintOptions)
+}
+
+// FingerprintWith returns the fingerprint of err using opts
+func FingerprintWith(err error, opts FingerprintOptions) string {
+	if err == nil {
+		return ""
+	}
+
+	var richErr *Error
+	if !As(err, &richErr) {
+		return hashComponents([]string{"message=" + NormalizeMessage(err.Error())})
+	}
+	return richErr.fingerprint(opts)
+}
+
+// Fingerprint returns the fingerprint of the error, see Fingerprint.
+// Redacted and response copies report the fingerprint of the original
+func (e *Error) Fingerprint() string {
+	if e == nil {
+		return ""
+	}
+	if e.originFingerprint != "" {
+		return e.originFingerprint
+	}
+	return e.fingerprint(fingerprintOptions)
+}
+
+func (e *Error) fingerprint(opts FingerprintOptions) string {
+	var components []string
+
+	if opts.Category {
+		components = append(components, "category="+e.Category.String())
+	}
+	if opts.TextCode {
+		components = append(components, "text_code="+e.TextCode)
+	}
+	if opts.Message {
+		components = append(components, "message="+NormalizeMessage(e.Message))
+	}
+	if opts.Location && e.Location != nil {
+		components = append(components, "location="+e.Location.Function)
+	}
+	for i, frame := range e.StackTrace {
+		if i >= opts.StackFrames {
+			break
+		}
+		components = append(components, "frame."+strconv.Itoa(i)+"="+frame.Function)
+	}
+
+	return hashComponents(components)
+}
+
+func hashComponents(components []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(components, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+var messageNormalizers = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`"[^"]*"|` + "`[^`]*`"), "<str>"},
+	// single quotes only when they open a value, so "user's" is kept
+	{regexp.MustCompile(`(^|[^\w])'[^']*'`), "${1}<str>"},
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), "<email>"},
+	{regexp.MustCompile(`\d+(\.\d+)?`), "<num>"},
+}
+
+// NormalizeMessage strips the variable parts of a message, quoted
+// values, UUIDs, emails and numbers, so messages that only differ by
+// those values produce the same fingerprint
+func NormalizeMessage(message string) string {
+	for _, n := range messageNormalizers {
+		message = n.pattern.ReplaceAllString(message, n.replacement)
+	}
+	return strings.Join(strings.Fields(message), " ")
+}
@@ -0,0 +1,194 @@
+package errors_test
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"testing"
+
+	"github.com/goliatone/go-errors"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"numbers", "order 1234 failed after 2.5s", "order <num> failed after <num>s"},
+		{"uuid", "user 550e8400-e29b-41d4-a716-446655440000 not found", "user <uuid> not found"},
+		{"email", "invite sent to jane.doe+test@example.com", "invite sent to <email>"},
+		{"double quotes", `field "email" is invalid`, "field <str> is invalid"},
+		{"single quotes", "table 'users' is locked", "table <str> is locked"},
+		{"backticks", "column `name` missing", "column <str> missing"},
+		{"apostrophe kept", "user's quota exceeded", "user's quota exceeded"},
+		{"whitespace", "  too   many\tspaces ", "too many spaces"},
+		{"unchanged", "connection refused", "connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.NormalizeMessage(tt.message); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func newOrderError(id int) *errors.Error {
+	return errors.New("order not found", errors.CategoryNotFound).
+		WithTextCode("ORDER_NOT_FOUND").
+		WithLocation(errors.Here()).
+		WithMetadata(map[string]any{"id": id})
+}
+
+func TestFingerprint(t *testing.T) {
+	first := newOrderError(1)
+	second := newOrderError(2)
+
+	fingerprint := errors.Fingerprint(first)
+	if len(fingerprint) != 16 {
+		t.Fatalf("Expected a 16 character fingerprint, got %q", fingerprint)
+	}
+	if fingerprint != errors.Fingerprint(second) {
+		t.Error("Expected occurrences of the same error to share a fingerprint")
+	}
+	if fingerprint != first.Fingerprint() {
+		t.Error("Expected Error.Fingerprint to match Fingerprint")
+	}
+
+	// the message is normalized before hashing
+	a := errors.New("user 42 not found", errors.CategoryNotFound).WithLocation(first.Location)
+	b := errors.New("user 7 not found", errors.CategoryNotFound).WithLocation(first.Location)
+	if errors.Fingerprint(a) != errors.Fingerprint(b) {
+		t.Error("Expected messages differing only by numbers to share a fingerprint")
+	}
+
+	// wrapping keeps the fingerprint of the outermost rich error
+	if errors.Fingerprint(errors.Join(first)) != fingerprint {
+		t.Error("Expected fingerprint of the first rich error in the chain")
+	}
+
+	other := errors.New("order not found", errors.CategoryConflict).
+		WithTextCode("ORDER_NOT_FOUND").
+		WithLocation(first.Location)
+	if errors.Fingerprint(other) == fingerprint {
+		t.Error("Expected a different category to change the fingerprint")
+	}
+
+	foreign := stderrors.New("dial tcp 10.0.0.1:5432: connection refused")
+	if errors.Fingerprint(foreign) != errors.Fingerprint(stderrors.New("dial tcp 10.0.0.2:5433: connection refused")) {
+		t.Error("Expected foreign errors to be fingerprinted by normalized message")
+	}
+
+	if errors.Fingerprint(nil) != "" {
+		t.Error("Expected empty fingerprint for nil")
+	}
+}
+
+func TestFingerprintWith(t *testing.T) {
+	here := newOrderError(1)
+	elsewhere := newOrderError(1).WithLocation(&errors.ErrorLocation{Function: "main.other"})
+
+	if errors.FingerprintWith(here, errors.DefaultFingerprintOptions) == errors.FingerprintWith(elsewhere, errors.DefaultFingerprintOptions) {
+		t.Error("Expected the location to change the fingerprint")
+	}
+
+	opts := errors.FingerprintOptions{Category: true, TextCode: true, Message: true}
+	if errors.FingerprintWith(here, opts) != errors.FingerprintWith(elsewhere, opts) {
+		t.Error("Expected the location to be ignored when disabled")
+	}
+
+	withStack := errors.FingerprintOptions{Category: true, StackFrames: 2}
+	traced := errors.New("boom", errors.CategoryInternal).WithStackTrace()
+	untraced := errors.New("boom", errors.CategoryInternal)
+	if errors.FingerprintWith(traced, withStack) == errors.FingerprintWith(untraced, withStack) {
+		t.Error("Expected stack frames to change the fingerprint")
+	}
+	if errors.FingerprintWith(traced, opts) != errors.FingerprintWith(untraced, opts) {
+		t.Error("Expected stack frames to be ignored when StackFrames is 0")
+	}
+}
+
+func TestSetFingerprintOptions(t *testing.T) {
+	defer errors.SetFingerprintOptions(errors.GetFingerprintOptions())
+
+	err := newOrderError(1)
+	before := err.Fingerprint()
+
+	errors.SetFingerprintOptions(errors.FingerprintOptions{Category: true})
+	if err.Fingerprint() == before {
+		t.Error("Expected the configured options to be used")
+	}
+	if err.Fingerprint() != errors.Fingerprint(errors.New("anything", errors.CategoryNotFound)) {
+		t.Error("Expected only the category to be hashed")
+	}
+}
+
+func TestFingerprint_Exposed(t *testing.T) {
+	err := newOrderError(1)
+
+	data, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("Unexpected error: %v", jsonErr)
+	}
+	var decoded map[string]any
+	if jsonErr := json.Unmarshal(data, &decoded); jsonErr != nil {
+		t.Fatalf("Unexpected error: %v", jsonErr)
+	}
+	if decoded["fingerprint"] != err.Fingerprint() {
+		t.Errorf("Expected fingerprint %s in JSON, got %v", err.Fingerprint(), decoded["fingerprint"])
+	}
+
+	found := false
+	for _, attr := range errors.ToSlogAttributes(err) {
+		if attr.Key == "fingerprint" {
+			found = attr.Value.String() == err.Fingerprint()
+		}
+	}
+	if !found {
+		t.Error("Expected fingerprint in slog attributes")
+	}
+}
+
+func TestFingerprint_SurvivesRedaction(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	previous := errors.GetRedactionPolicy()
+	errors.SetRedactionPolicy(errors.RedactionPolicy{
+		JSON:   errors.DefaultRedactor(),
+		Client: errors.DefaultRedactor(),
+	})
+	t.Cleanup(func() { errors.SetRedactionPolicy(previous) })
+
+	jane := errors.New("token "+testJWT+" rejected for jane", errors.CategoryAuth).WithCode(401)
+	john := errors.New("token "+testJWT+" rejected for john", errors.CategoryAuth).WithCode(401)
+	jane.Location, john.Location = nil, nil
+
+	if jane.Fingerprint() == john.Fingerprint() {
+		t.Fatal("Expected distinct fingerprints before redaction")
+	}
+
+	redacted := errors.DefaultRedactor().Redact(jane)
+	if redacted.Fingerprint() != jane.Fingerprint() {
+		t.Error("Expected the redacted copy to keep the original fingerprint")
+	}
+
+	response := jane.ToErrorResponse(false, nil)
+	if response.Error.Fingerprint() != jane.Fingerprint() {
+		t.Error("Expected the response to keep the original fingerprint")
+	}
+
+	data, jsonErr := json.Marshal(response)
+	if jsonErr != nil {
+		t.Fatalf("Unexpected error: %v", jsonErr)
+	}
+	var decoded struct {
+		Error map[string]any `json:"error"`
+	}
+	if jsonErr := json.Unmarshal(data, &decoded); jsonErr != nil {
+		t.Fatalf("Unexpected error: %v", jsonErr)
+	}
+	if decoded.Error["fingerprint"] != jane.Fingerprint() {
+		t.Errorf("Expected response fingerprint %s, got %v", jane.Fingerprint(), decoded.Error["fingerprint"])
+	}
+}
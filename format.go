@@ -0,0 +1,163 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Format implements fmt.Formatter for Error
+//
+//	%s, %v  short form, same as Error()
+//	%q      quoted short form
+//	%+v     full chain with location, metadata, validation errors and stack,
+//	        redacted by the Log redactor of the RedactionPolicy
+//	%#v     Go-syntax representation
+func (e *Error) Format(s fmt.State, verb rune) {
+	if e == nil {
+		io.WriteString(s, "<nil>")
+		return
+	}
+
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('#'):
+			io.WriteString(s, e.goString())
+		case s.Flag('+'):
+			io.WriteString(s, redactionPolicy.Log.Redact(e).detailedString())
+		default:
+			io.WriteString(s, e.Error())
+		}
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(*errors.Error=%s)", verb, e.Error())
+	}
+}
+
+// Format implements fmt.Formatter for RetryableError, adding
+// retry information to the detailed and Go-syntax forms
+func (r *RetryableError) Format(s fmt.State, verb rune) {
+	if r == nil {
+		io.WriteString(s, "<nil>")
+		return
+	}
+
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('#'):
+			fmt.Fprintf(s, "&errors.RetryableError{BaseError:%#v, retryable:%t, baseDelay:%d}",
+				r.BaseError, r.retryable, r.baseDelay)
+		case s.Flag('+'):
+			r = redactionPolicy.Log.RedactError(r).(*RetryableError)
+			if r.BaseError != nil {
+				io.WriteString(s, r.BaseError.detailedString())
+			} else {
+				io.WriteString(s, r.Error())
+			}
+			fmt.Fprintf(s, "\n    retryable: %t\n    retry_delay: %s", r.IsRetryable(), r.baseDelay)
+		default:
+			io.WriteString(s, r.Error())
+		}
+	case 's':
+		io.WriteString(s, r.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", r.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(*errors.RetryableError=%s)", verb, r.Error())
+	}
+}
+
+// detailedString renders the error and its full source chain
+// regardless of the global Verbose flag
+func (e *Error) detailedString() string {
+	var b strings.Builder
+
+	if e.TextCode != "" {
+		fmt.Fprintf(&b, "[%s:%s] %s", e.Category, e.TextCode, e.Message)
+	} else {
+		fmt.Fprintf(&b, "[%s] %s", e.Category, e.Message)
+	}
+
+	if e.Code != 0 {
+		fmt.Fprintf(&b, "\n    code: %d", e.Code)
+	}
+
+	if e.PublicMessage != "" {
+		fmt.Fprintf(&b, "\n    public_message: %s", e.PublicMessage)
+	}
+
+	fmt.Fprintf(&b, "\n    severity: %s", e.Severity)
+
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, "\n    request_id: %s", e.RequestID)
+	}
+
+	if e.Location != nil {
+		fmt.Fprintf(&b, "\n    location: %s", e.Location.String())
+		if e.Location.Function != "" {
+			fmt.Fprintf(&b, " (%s)", e.Location.Function)
+		}
+	}
+
+	if len(e.Metadata) > 0 {
+		keys := make([]string, 0, len(e.Metadata))
+		for k := range e.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b.WriteString("\n    metadata:")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "\n        %s: %v", k, e.Metadata[k])
+		}
+	}
+
+	if len(e.ValidationErrors) > 0 {
+		b.WriteString("\n    validation:")
+		for _, fieldErr := range e.ValidationErrors {
+			fmt.Fprintf(&b, "\n        %s", fieldErr.Error())
+		}
+	}
+
+	if len(e.StackTrace) > 0 {
+		b.WriteString("\n    stack trace:")
+		for _, frame := range e.StackTrace {
+			fmt.Fprintf(&b, "\n        %s\n            %s:%d", frame.Function, frame.File, frame.Line)
+		}
+	}
+
+	if e.Source != nil {
+		fmt.Fprintf(&b, "\ncaused by: %+v", e.Source)
+	}
+
+	return b.String()
+}
+
+// goString renders the error using Go syntax
+func (e *Error) goString() string {
+	var b strings.Builder
+
+	b.WriteString("&errors.Error{")
+	fmt.Fprintf(&b, "Category:%q", string(e.Category))
+	fmt.Fprintf(&b, ", Code:%d", e.Code)
+	fmt.Fprintf(&b, ", TextCode:%q", e.TextCode)
+	fmt.Fprintf(&b, ", Message:%q", e.Message)
+	fmt.Fprintf(&b, ", PublicMessage:%q", e.PublicMessage)
+	fmt.Fprintf(&b, ", Source:%#v", e.Source)
+	fmt.Fprintf(&b, ", ValidationErrors:%#v", e.ValidationErrors)
+	fmt.Fprintf(&b, ", Metadata:%#v", e.Metadata)
+	fmt.Fprintf(&b, ", RequestID:%q", e.RequestID)
+	fmt.Fprintf(&b, ", Timestamp:%#v", e.Timestamp)
+	fmt.Fprintf(&b, ", StackTrace:%#v", e.StackTrace)
+	fmt.Fprintf(&b, ", Location:%#v", e.Location)
+	fmt.Fprintf(&b, ", Severity:%d", int(e.Severity))
+	b.WriteString("}")
+
+	return b.String()
+}
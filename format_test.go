@@ -0,0 +1,133 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-errors"
+)
+
+func TestError_Format(t *testing.T) {
+	source := errors.New("connection refused", errors.CategoryExternal).
+		WithTextCode("DB_DOWN")
+	err := errors.New("lookup failed", errors.CategoryInternal).
+		WithCode(500).
+		WithTextCode("LOOKUP_FAILED").
+		WithRequestID("req-1").
+		WithMetadata(map[string]any{"user_id": 42}).
+		WithStackTrace()
+	err.Source = source
+	err.ValidationErrors = errors.ValidationErrors{{Field: "email", Message: "required"}}
+
+	if got := fmt.Sprintf("%v", err); got != err.Error() {
+		t.Errorf("Expected %%v to match Error(), got %q", got)
+	}
+	if got := fmt.Sprintf("%s", err); got != err.Error() {
+		t.Errorf("Expected %%s to match Error(), got %q", got)
+	}
+	if got := fmt.Sprintf("%q", err); got != fmt.Sprintf("%q", err.Error()) {
+		t.Errorf("Expected %%q to quote Error(), got %s", got)
+	}
+
+	detailed := fmt.Sprintf("%+v", err)
+	for _, want := range []string{
+		"[internal:LOOKUP_FAILED] lookup failed",
+		"code: 500",
+		"severity: ERROR",
+		"request_id: req-1",
+		"location: format_test.go:",
+		"user_id: 42",
+		"email: required",
+		"stack trace:",
+		"TestError_Format",
+		"caused by: [external:DB_DOWN] connection refused",
+	} {
+		if !strings.Contains(detailed, want) {
+			t.Errorf("Expected %%+v output to contain %q, got:\n%s", want, detailed)
+		}
+	}
+
+	goSyntax := fmt.Sprintf("%#v", err)
+	for _, want := range []string{
+		`&errors.Error{Category:"internal"`,
+		`TextCode:"LOOKUP_FAILED"`,
+		`Source:&errors.Error{Category:"external"`,
+	} {
+		if !strings.Contains(goSyntax, want) {
+			t.Errorf("Expected %%#v output to contain %q, got:\n%s", want, goSyntax)
+		}
+	}
+}
+
+func TestError_Format_IgnoresVerbose(t *testing.T) {
+	original := errors.Verbose
+	errors.Verbose = false
+	defer func() { errors.Verbose = original }()
+
+	err := errors.New("no verbose", errors.CategoryInternal)
+	if !strings.Contains(fmt.Sprintf("%+v", err), "location:") {
+		t.Error("Expected detailed format to include location without the Verbose flag")
+	}
+	if strings.Contains(fmt.Sprintf("%v", err), "location:") {
+		t.Error("Expected short format to omit location without the Verbose flag")
+	}
+}
+
+func TestError_Format_ForeignSource(t *testing.T) {
+	err := errors.Wrap(fmt.Errorf("disk full"), errors.CategoryInternal, "write failed")
+
+	detailed := fmt.Sprintf("%+v", err)
+	if !strings.Contains(detailed, "caused by: disk full") {
+		t.Errorf("Expected foreign source in chain, got:\n%s", detailed)
+	}
+}
+
+func TestRetryableError_Format(t *testing.T) {
+	err := errors.NewRetryable("service unavailable", errors.CategoryExternal).
+		WithRetryDelay(2 * time.Second)
+
+	if got := fmt.Sprintf("%v", err); got != err.Error() {
+		t.Errorf("Expected %%v to match Error(), got %q", got)
+	}
+
+	detailed := fmt.Sprintf("%+v", err)
+	for _, want := range []string{"service unavailable", "retryable: true", "retry_delay: 2s"} {
+		if !strings.Contains(detailed, want) {
+			t.Errorf("Expected %%+v output to contain %q, got:\n%s", want, detailed)
+		}
+	}
+
+	goSyntax := fmt.Sprintf("%#v", err)
+	if !strings.HasPrefix(goSyntax, "&errors.RetryableError{BaseError:&errors.Error{") {
+		t.Errorf("Unexpected %%#v output: %s", goSyntax)
+	}
+	if !strings.Contains(goSyntax, "retryable:true") {
+		t.Errorf("Expected %%#v output to include retry flag, got %s", goSyntax)
+	}
+}
+
+func TestError_Format_RedactsDetailed(t *testing.T) {
+	defer errors.SetRedactionPolicy(errors.GetRedactionPolicy())
+	errors.SetRedactionPolicy(errors.RedactionPolicy{Log: errors.DefaultRedactor()})
+
+	err := errors.Wrap(stderrors.New("mail to jane@example.com bounced"), errors.CategoryExternal, "notify jane@example.com").
+		WithMetadata(map[string]any{"password": "hunter2"})
+
+	formatted := map[string]string{
+		"error":     fmt.Sprintf("%+v", err),
+		"retryable": fmt.Sprintf("%+v", errors.NewRetryable("retry", errors.CategoryExternal).WithMetadata(map[string]any{"password": "hunter2"})),
+		"multi":     fmt.Sprintf("%+v", errors.WrapAll(errors.CategoryOperation, "batch failed", err, stderrors.New("token for jane@example.com"))),
+	}
+	for name, detailed := range formatted {
+		if strings.Contains(detailed, "jane@example.com") || strings.Contains(detailed, "hunter2") {
+			t.Errorf("Expected %s %%+v output to be redacted, got:\n%s", name, detailed)
+		}
+	}
+
+	if err.Metadata["password"] != "hunter2" {
+		t.Error("Expected formatting to leave the original error untouched")
+	}
+}
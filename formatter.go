@@ -0,0 +1,312 @@
+package errors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResponseFormatter renders an error response in one media type
+type ResponseFormatter interface {
+	// MediaType is matched against the Accept header, e.g. application/xml
+	MediaType() string
+	// ContentType is the Content-Type header written with the response
+	ContentType() string
+	// Format writes the body of response, r is the request being answered
+	Format(w io.Writer, r *http.Request, response ErrorResponse, status int) error
+}
+
+// JSONFormatter writes the ErrorResponse envelope as JSON
+type JSONFormatter struct{}
+
+func (JSONFormatter) MediaType() string   { return "application/json" }
+func (JSONFormatter) ContentType() string { return "application/json; charset=utf-8" }
+
+func (JSONFormatter) Format(w io.Writer, _ *http.Request, response ErrorResponse, _ int) error {
+	return json.NewEncoder(w).Encode(response)
+}
+
+// ProblemFormatter writes RFC 9457 problem details, the instance is the
+// request path
+type ProblemFormatter struct{}
+
+func (ProblemFormatter) MediaType() string   { return ProblemContentType }
+func (ProblemFormatter) ContentType() string { return ProblemContentType }
+
+func (ProblemFormatter) Format(w io.Writer, r *http.Request, response ErrorResponse, status int) error {
+	instance := ""
+	if r != nil && r.URL != nil {
+		instance = r.URL.Path
+	}
+	return json.NewEncoder(w).Encode(problemFromResponse(response.Error, status, instance))
+}
+
+// XMLFormatter writes the response as an <error> document
+type XMLFormatter struct{}
+
+func (XMLFormatter) MediaType() string   { return "application/xml" }
+func (XMLFormatter) ContentType() string { return "application/xml; charset=utf-8" }
+
+type xmlError struct {
+	XMLName          xml.Name        `xml:"error"`
+	Category         string          `xml:"category"`
+	Code             int             `xml:"code,omitempty"`
+	TextCode         string          `xml:"text_code,omitempty"`
+	Message          string          `xml:"message"`
+	PublicMessage    string          `xml:"public_message,omitempty"`
+	RequestID        string          `xml:"request_id,omitempty"`
+	Timestamp        string          `xml:"timestamp,omitempty"`
+	Severity         string          `xml:"severity"`
+	ValidationErrors []xmlFieldError `xml:"validation_errors>field_error,omitempty"`
+	Metadata         []xmlEntry      `xml:"metadata>entry,omitempty"`
+}
+
+type xmlFieldError struct {
+	Field   string `xml:"field,attr"`
+	Code    string `xml:"code,attr,omitempty"`
+	Message string `xml:",chardata"`
+}
+
+type xmlEntry struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (XMLFormatter) Format(w io.Writer, _ *http.Request, response ErrorResponse, status int) error {
+	e := response.Error
+	doc := xmlError{
+		Category:      e.Category.String(),
+		Code:          status,
+		TextCode:      e.TextCode,
+		Message:       e.Message,
+		PublicMessage: e.PublicMessage,
+		RequestID:     e.RequestID,
+		Severity:      e.Severity.String(),
+	}
+	if !e.Timestamp.IsZero() {
+		doc.Timestamp = e.Timestamp.Format(time.RFC3339Nano)
+	}
+	for _, fieldErr := range e.ValidationErrors {
+		doc.ValidationErrors = append(doc.ValidationErrors, xmlFieldError{
+			Field:   fieldErr.Field,
+			Code:    fieldErr.Code,
+			Message: fieldErr.Message,
+		})
+	}
+	for _, key := range sortedKeys(e.Metadata) {
+		doc.Metadata = append(doc.Metadata, xmlEntry{Key: key, Value: fmt.Sprint(e.Metadata[key])})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// TextFormatter writes a short plain text description
+type TextFormatter struct{}
+
+func (TextFormatter) MediaType() string   { return "text/plain" }
+func (TextFormatter) ContentType() string { return "text/plain; charset=utf-8" }
+
+func (TextFormatter) Format(w io.Writer, _ *http.Request, response ErrorResponse, status int) error {
+	e := response.Error
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s\n%s\n", status, http.StatusText(status), e.Message)
+	if e.TextCode != "" {
+		fmt.Fprintf(&b, "code: %s\n", e.TextCode)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, "request id: %s\n", e.RequestID)
+	}
+	for _, fieldErr := range e.ValidationErrors {
+		fmt.Fprintf(&b, "- %s: %s\n", fieldErr.Field, fieldErr.Message)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// DefaultHTMLTemplate renders the minimal page of HTMLFormatter. It is
+// executed with a map holding Status, StatusText and Error
+var DefaultHTMLTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Status }} {{ .StatusText }}</title>
+<style>body{font-family:system-ui,sans-serif;max-width:40rem;margin:4rem auto;padding:0 1rem;color:#222}small{color:#666}</style>
+</head>
+<body>
+<h1>{{ .Status }} {{ .StatusText }}</h1>
+<p>{{ .Error.Message }}</p>
+{{- with .Error.ValidationErrors }}
+<ul>
+{{- range . }}
+<li><strong>{{ if .Label }}{{ .Label }}{{ else }}{{ .Field }}{{ end }}</strong>: {{ .Message }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- with .Error.RequestID }}
+<p><small>Request ID: {{ . }}</small></p>
+{{- end }}
+</body>
+</html>
+`))
+
+// HTMLFormatter writes a minimal HTML page, Template defaults to
+// DefaultHTMLTemplate
+type HTMLFormatter struct {
+	Template *template.Template
+}
+
+func (HTMLFormatter) MediaType() string   { return "text/html" }
+func (HTMLFormatter) ContentType() string { return "text/html; charset=utf-8" }
+
+func (f HTMLFormatter) Format(w io.Writer, _ *http.Request, response ErrorResponse, status int) error {
+	tmpl := f.Template
+	if tmpl == nil {
+		tmpl = DefaultHTMLTemplate
+	}
+	return tmpl.Execute(w, map[string]any{
+		"Status":     status,
+		"StatusText": http.StatusText(status),
+		"Error":      response.Error,
+	})
+}
+
+// Negotiator picks a ResponseFormatter from an Accept header
+type Negotiator struct {
+	formatters []ResponseFormatter
+}
+
+// NewNegotiator creates a negotiator, the first formatter is used when
+// the Accept header is missing or matches none
+func NewNegotiator(formatters ...ResponseFormatter) *Negotiator {
+	return &Negotiator{formatters: formatters}
+}
+
+// DefaultNegotiator offers JSON (the default), problem+json, XML, text
+// and HTML
+func DefaultNegotiator() *Negotiator {
+	return NewNegotiator(JSONFormatter{}, ProblemFormatter{}, XMLFormatter{}, TextFormatter{}, HTMLFormatter{})
+}
+
+// Formatters returns the formatters in preference order
+func (n *Negotiator) Formatters() []ResponseFormatter {
+	return append([]ResponseFormatter(nil), n.formatters...)
+}
+
+// Negotiate returns the formatter with the highest quality in accept.
+// The most specific matching media range sets the quality of a
+// formatter (application/xml over application/* over */*), ties keep
+// the formatter order
+func (n *Negotiator) Negotiate(accept string) ResponseFormatter {
+	if len(n.formatters) == 0 {
+		return JSONFormatter{}
+	}
+
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return n.formatters[0]
+	}
+
+	best, bestQuality := n.formatters[0], 0.0
+	for _, formatter := range n.formatters {
+		if q := acceptQuality(ranges, formatter.MediaType()); q > bestQuality {
+			best, bestQuality = formatter, q
+		}
+	}
+	return best
+}
+
+// Write negotiates the formatter from the Accept header of r and writes
+// the response with its status. HEAD requests get no body, a nil r gets
+// the default formatter
+func (n *Negotiator) Write(w http.ResponseWriter, r *http.Request, response ErrorResponse, status int) error {
+	w.Header().Add("Vary", "Accept")
+	return writeFormatted(w, r, n.Negotiate(requestHeader(r, "Accept")), response, status)
+}
+
+func writeFormatted(w http.ResponseWriter, r *http.Request, formatter ResponseFormatter, response ErrorResponse, status int) error {
+	w.Header().Set("Content-Type", formatter.ContentType())
+	w.WriteHeader(status)
+	if r != nil && r.Method == http.MethodHead {
+		return nil
+	}
+	return formatter.Format(w, r, response, status)
+}
+
+type mediaRange struct {
+	typ, subtype string
+	quality      float64
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(mediaType)), "/")
+		if !ok {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(name), "q") {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q >= 0 && q <= 1 {
+					quality = q
+				}
+			}
+		}
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, quality: quality})
+	}
+	return ranges
+}
+
+// acceptQuality returns the quality of the most specific range matching mediaType
+func acceptQuality(ranges []mediaRange, mediaType string) float64 {
+	typ, subtype, _ := strings.Cut(strings.ToLower(mediaType), "/")
+
+	quality, specificity := 0.0, -1
+	for _, r := range ranges {
+		var s int
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*" && r.subtype == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			quality, specificity = r.quality, s
+		}
+	}
+	return quality
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
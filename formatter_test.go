@@ -0,0 +1,200 @@
+package errors_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/go-errors"
+)
+
+func TestNegotiator_Negotiate(t *testing.T) {
+	n := errors.DefaultNegotiator()
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"missing", "", "application/json"},
+		{"any", "*/*", "application/json"},
+		{"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "text/html"},
+		{"xml", "application/xml", "application/xml"},
+		{"problem", "application/problem+json, application/json;q=0.5", "application/problem+json"},
+		{"text wildcard", "text/*", "text/plain"},
+		{"quality", "text/plain;q=0.2, application/xml;q=0.7", "application/xml"},
+		{"specific beats wildcard", "*/*;q=0.9, application/json;q=0.1", "application/problem+json"},
+		{"unsupported", "image/png", "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.Negotiate(tt.accept).MediaType(); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	custom := errors.NewNegotiator(errors.XMLFormatter{})
+	if got := custom.Negotiate("application/json").MediaType(); got != "application/xml" {
+		t.Errorf("Expected the first formatter as fallback, got %s", got)
+	}
+}
+
+func formatResponse(t *testing.T, f errors.ResponseFormatter, e *errors.Error) string {
+	t.Helper()
+	var buf bytes.Buffer
+	r := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+	if err := f.Format(&buf, r, e.ToErrorResponse(false, nil), e.HTTPStatus()); err != nil {
+		t.Fatalf("Failed to format: %v", err)
+	}
+	return buf.String()
+}
+
+func TestFormatters(t *testing.T) {
+	withMode(t, errors.ModeDevelopment)
+
+	e := errors.NewValidation("order <invalid>",
+		errors.FieldError{Field: "qty", Message: "must be positive", Code: "validation_min"},
+	).WithTextCode("ORDER_INVALID").
+		WithRequestID("req-5").
+		WithMetadata(map[string]any{"order_id": 1})
+
+	t.Run("xml", func(t *testing.T) {
+		out := formatResponse(t, errors.XMLFormatter{}, e)
+		var doc struct {
+			XMLName  xml.Name `xml:"error"`
+			Code     int      `xml:"code"`
+			TextCode string   `xml:"text_code"`
+			Message  string   `xml:"message"`
+			Fields   []struct {
+				Field   string `xml:"field,attr"`
+				Message string `xml:",chardata"`
+			} `xml:"validation_errors>field_error"`
+			Metadata []struct {
+				Key   string `xml:"key,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"metadata>entry"`
+		}
+		if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatalf("Failed to parse %q: %v", out, err)
+		}
+		if doc.Code != 400 || doc.TextCode != "ORDER_INVALID" || doc.Message != "order <invalid>" {
+			t.Errorf("Unexpected document %+v", doc)
+		}
+		if len(doc.Fields) != 1 || doc.Fields[0].Field != "qty" || doc.Fields[0].Message != "must be positive" {
+			t.Errorf("Unexpected field errors %+v", doc.Fields)
+		}
+		if len(doc.Metadata) != 1 || doc.Metadata[0].Key != "order_id" || doc.Metadata[0].Value != "1" {
+			t.Errorf("Unexpected metadata %+v", doc.Metadata)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		out := formatResponse(t, errors.TextFormatter{}, e)
+		for _, want := range []string{"400 Bad Request", "order <invalid>", "code: ORDER_INVALID", "request id: req-5", "- qty: must be positive"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected %q in %q", want, out)
+			}
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		out := formatResponse(t, errors.HTMLFormatter{}, e)
+		for _, want := range []string{"<title>400 Bad Request</title>", "order &lt;invalid&gt;", "<strong>qty</strong>: must be positive", "Request ID: req-5"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected %q in %q", want, out)
+			}
+		}
+	})
+
+	t.Run("problem", func(t *testing.T) {
+		out := formatResponse(t, errors.ProblemFormatter{}, e)
+		if !strings.Contains(out, `"instance":"/orders/1"`) || !strings.Contains(out, `"status":400`) {
+			t.Errorf("Unexpected problem document %q", out)
+		}
+	})
+}
+
+func TestNegotiator_WriteNilRequest(t *testing.T) {
+	e := errors.New("missing", errors.CategoryNotFound)
+
+	rec := httptest.NewRecorder()
+	if err := errors.DefaultNegotiator().Write(rec, nil, e.ToErrorResponse(false, nil), e.HTTPStatus()); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	if rec.Code != http.StatusNotFound || rec.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("Unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if rec.Body.Len() == 0 {
+		t.Error("Expected a body for a nil request")
+	}
+}
+
+func TestProblemFormatter_ProductionHidesDetails(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	e := errors.New("db timeout", errors.CategoryInternal).
+		WithCode(500).
+		WithStackTrace()
+	e.Location = errors.CallerLocation(0)
+
+	var buf bytes.Buffer
+	r := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+	if err := (errors.ProblemFormatter{}).Format(&buf, r, e.ToErrorResponse(true, e.StackTrace), 500); err != nil {
+		t.Fatalf("Failed to format: %v", err)
+	}
+	out := buf.String()
+	for _, leak := range []string{"stack_trace", "location", "db timeout"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("Expected %q to be hidden in %q", leak, out)
+		}
+	}
+}
+
+func TestAdapt_NegotiatesFormat(t *testing.T) {
+	h := errors.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("missing", errors.CategoryNotFound)
+	})
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json; charset=utf-8"},
+		{"text/html,*/*;q=0.8", "text/html; charset=utf-8"},
+		{"application/problem+json", errors.ProblemContentType},
+		{"application/xml", "application/xml; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.accept != "" {
+			r.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got := w.Header().Get("Content-Type"); got != tt.want {
+			t.Errorf("Expected %s for %q, got %s", tt.want, tt.accept, got)
+		}
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for %q, got %d", tt.accept, w.Code)
+		}
+	}
+
+	textOnly := errors.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("missing", errors.CategoryNotFound)
+	}, errors.WithFormatters(errors.TextFormatter{}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	textOnly.ServeHTTP(w, r)
+	if got := w.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Expected text fallback, got %s", got)
+	}
+}
@@ -0,0 +1,74 @@
+package errors
+
+// Freeze marks the error as immutable and returns it. Fluent methods
+// (WithMetadata, WithCode, WithSeverity, ...) called on a frozen error
+// return a modified clone and leave the receiver untouched, so a frozen
+// error can be shared and enriched from many goroutines.
+//
+// Freeze itself writes to the error, call it before the error is shared,
+// typically when declaring package level values:
+//
+//	var ErrUserNotFound = errors.New("user not found", errors.CategoryNotFound).
+//		WithTextCode("USER_NOT_FOUND").
+//		Freeze()
+//
+// Fields assigned directly are not protected. Clone returns a mutable copy
+func (e *Error) Freeze() *Error {
+	if e == nil {
+		return nil
+	}
+	e.frozen = true
+	return e
+}
+
+// IsFrozen returns true if fluent methods return a clone instead of
+// mutating the error
+func (e *Error) IsFrozen() bool {
+	return e != nil && e.frozen
+}
+
+// mutable returns e, or a mutable clone of e when it is frozen
+func (e *Error) mutable() *Error {
+	if e != nil && e.frozen {
+		return e.Clone()
+	}
+	return e
+}
+
+// Freeze marks the retryable error as immutable, see Error.Freeze. A
+// retryable error without a BaseError is returned unchanged
+func (r *RetryableError) Freeze() *RetryableError {
+	if r.BaseError != nil {
+		r.BaseError.Freeze()
+	}
+	return r
+}
+
+// mutable returns r, or a mutable copy of r when it is frozen
+func (r *RetryableError) mutable() *RetryableError {
+	if r.BaseError == nil || !r.frozen {
+		return r
+	}
+	return &RetryableError{
+		BaseError: r.BaseError.Clone(),
+		retryable: r.retryable,
+		baseDelay: r.baseDelay,
+	}
+}
+
+// Freeze marks the multi error as immutable, see Error.Freeze. A multi
+// error without a BaseError is returned unchanged
+func (m *MultiError) Freeze() *MultiError {
+	if m.BaseError != nil {
+		m.BaseError.Freeze()
+	}
+	return m
+}
+
+// mutable returns m, or a mutable clone of m when it is frozen
+func (m *MultiError) mutable() *MultiError {
+	if m.BaseError != nil && m.frozen {
+		return m.Clone()
+	}
+	return m
+}
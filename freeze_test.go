@@ -0,0 +1,214 @@
+package errors_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-errors"
+)
+
+// The concurrent tests below are meant to run with the race detector:
+//
+//	go test -race -run Frozen ./...
+
+const frozenWorkers = 64
+
+func TestError_Freeze(t *testing.T) {
+	base := errors.New("user not found", errors.CategoryNotFound).
+		WithTextCode("USER_NOT_FOUND").
+		WithMetadata(map[string]any{"table": "users"}).
+		Freeze()
+
+	if !base.IsFrozen() {
+		t.Fatal("Expected error to be frozen")
+	}
+
+	enriched := base.WithMetadata(map[string]any{"id": 1}).WithCode(404).WithSeverity(errors.SeverityWarning)
+
+	if enriched == base {
+		t.Fatal("Expected fluent calls on a frozen error to return a clone")
+	}
+	if enriched.IsFrozen() {
+		t.Error("Expected the clone to be mutable")
+	}
+	if base.Code != 0 || base.Severity != errors.SeverityError || len(base.Metadata) != 1 {
+		t.Errorf("Expected frozen error to be unchanged, got code=%d severity=%s metadata=%d",
+			base.Code, base.Severity, len(base.Metadata))
+	}
+	if enriched.Code != 404 || enriched.Metadata["id"] != 1 || enriched.Metadata["table"] != "users" {
+		t.Errorf("Expected clone to carry the changes, got code=%d metadata=%v", enriched.Code, enriched.Metadata)
+	}
+
+	// a mutable clone keeps mutating in place
+	if again := enriched.WithRequestID("req-1"); again != enriched {
+		t.Error("Expected fluent calls on the clone to return the same error")
+	}
+
+	if !errors.Is(enriched, base) {
+		t.Error("Expected the clone to match the frozen error with errors.Is")
+	}
+}
+
+func TestError_CloneOfFrozenIsMutable(t *testing.T) {
+	base := errors.New("boom", errors.CategoryInternal).Freeze()
+	clone := base.Clone()
+
+	if clone.IsFrozen() {
+		t.Error("Expected Clone to return a mutable error")
+	}
+	if clone.WithCode(500) != clone {
+		t.Error("Expected fluent calls on the clone to mutate it")
+	}
+}
+
+func TestSentinels_AreFrozen(t *testing.T) {
+	sentinels := []*errors.Error{
+		errors.ErrValidation, errors.ErrAuth, errors.ErrAuthz, errors.ErrOperation,
+		errors.ErrNotFound, errors.ErrConflict, errors.ErrRateLimit, errors.ErrBadInput,
+		errors.ErrInternal, errors.ErrExternal, errors.ErrMiddleware, errors.ErrRouting,
+		errors.ErrHandler, errors.ErrMethodNotAllowed, errors.ErrCommand,
+	}
+
+	for _, sentinel := range sentinels {
+		if !sentinel.IsFrozen() {
+			t.Errorf("Expected sentinel %s to be frozen", sentinel.Category)
+		}
+	}
+
+	err := errors.ErrNotFound.WithCode(404)
+	if errors.ErrNotFound.Code != 0 {
+		t.Error("Expected ErrNotFound to be unchanged")
+	}
+	if err.Code != 404 || !errors.Is(err, errors.ErrNotFound) {
+		t.Error("Expected enriched sentinel to keep matching ErrNotFound")
+	}
+}
+
+func TestFrozen_ConcurrentEnrichment(t *testing.T) {
+	shared := errors.New("quota exceeded", errors.CategoryRateLimit).
+		WithMetadata(map[string]any{"limit": 100}).
+		Freeze()
+	location := shared.Location
+
+	results := make([]*errors.Error, frozenWorkers)
+
+	var wg sync.WaitGroup
+	for i := range frozenWorkers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// every fluent method is called on the shared error itself
+			withCode := shared.WithCode(429)
+			_ = shared.WithTextCode("QUOTA_EXCEEDED")
+			_ = shared.WithSeverity(errors.SeverityWarning)
+			_ = shared.WithLocation(errors.Here())
+			_ = shared.WithStackTrace()
+			_ = shared.WithRequestID("shared")
+
+			results[i] = shared.WithMetadata(map[string]any{"worker": i}).WithRequestID(fmt.Sprintf("req-%d", i))
+			if withCode == shared || withCode.Code != 429 {
+				t.Errorf("Worker %d: expected WithCode to return a clone", i)
+			}
+
+			// reads of the shared error are safe alongside the writes above
+			_ = shared.Error()
+			_ = errors.Is(results[i], shared)
+			_ = errors.ToSlogAttributes(shared)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if result.RequestID != fmt.Sprintf("req-%d", i) || result.Metadata["worker"] != i {
+			t.Errorf("Worker %d: expected its own request ID and metadata, got %s %v", i, result.RequestID, result.Metadata)
+		}
+		if result.Metadata["limit"] != 100 {
+			t.Errorf("Worker %d: expected inherited metadata", i)
+		}
+	}
+
+	if shared.RequestID != "" || shared.Code != 0 || len(shared.Metadata) != 1 || shared.Location != location {
+		t.Error("Expected shared error to be unchanged after concurrent enrichment")
+	}
+}
+
+func TestFrozen_ConcurrentSentinelEnrichment(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range frozenWorkers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = errors.ErrNotFound.WithCode(404)
+			err := errors.ErrNotFound.WithMetadata(map[string]any{"id": i})
+			if !errors.Is(errors.Wrap(err, errors.CategoryHandler, "lookup failed"), errors.ErrNotFound) {
+				t.Errorf("Worker %d: expected wrapped error to match ErrNotFound", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if errors.ErrNotFound.Metadata != nil || errors.ErrNotFound.Code != 0 {
+		t.Error("Expected ErrNotFound to be unchanged")
+	}
+}
+
+func TestFrozen_ConcurrentRetryableAndMulti(t *testing.T) {
+	retryable := errors.NewRetryable("upstream timeout", errors.CategoryExternal).Freeze()
+	multi := errors.WrapAll(errors.CategoryOperation, "batch failed",
+		errors.New("a", errors.CategoryValidation),
+		errors.New("b", errors.CategoryConflict),
+	).Freeze()
+
+	var wg sync.WaitGroup
+	for i := range frozenWorkers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			_ = retryable.WithCode(503)
+			_ = retryable.WithRetryable(false)
+			_ = retryable.WithSeverity(errors.SeverityWarning)
+			r := retryable.WithRetryDelay(time.Duration(i) * time.Millisecond)
+			if r == retryable || r.RetryDelay(0) != time.Duration(i)*time.Millisecond {
+				t.Errorf("Worker %d: expected a retryable clone with its own delay", i)
+			}
+
+			_ = multi.WithSeverity(errors.SeverityCritical)
+			_ = multi.WithCode(500)
+			m := multi.WithMetadata(map[string]any{"worker": i})
+			if m == multi || len(m.Causes) != 2 {
+				t.Errorf("Worker %d: expected a multi error clone with its causes", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if !retryable.IsRetryable() || retryable.Code != 0 {
+		t.Error("Expected shared retryable error to be unchanged")
+	}
+	if multi.Metadata != nil || multi.Severity != errors.SeverityError {
+		t.Error("Expected shared multi error to be unchanged")
+	}
+}
+
+func TestFreeze_NilBaseError(t *testing.T) {
+	retryable := (&errors.RetryableError{}).Freeze()
+	if got := retryable.WithRetryable(true).WithRetryDelay(time.Second).WithCode(503).WithMetadata(map[string]any{"a": 1}); got != retryable {
+		t.Error("Expected a retryable error without base error to be returned unchanged")
+	}
+	if retryable.IsFrozen() {
+		t.Error("Expected a retryable error without base error to not be frozen")
+	}
+
+	multi := (&errors.MultiError{}).Freeze()
+	if got := multi.WithMetadata(map[string]any{"a": 1}).WithSeverity(errors.SeverityWarning).WithStackTrace(); got != multi {
+		t.Error("Expected a multi error without base error to be returned unchanged")
+	}
+
+	var nilErr *errors.Error
+	if got := nilErr.Freeze().WithCode(404).WithTextCode("X").WithMetadata(map[string]any{"a": 1}).HumanizeValidation(nil, ""); got != nil {
+		t.Errorf("Expected fluent methods on a nil error to return nil, got %v", got)
+	}
+}
@@ -2,4 +2,10 @@ module github.com/goliatone/go-errors
 
 go 1.23.4
 
-require github.com/go-ozzo/ozzo-validation/v4 v4.3.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/go-ozzo/ozzo-validation/v4 v4.3.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/asaskevich/govalidator v0.0.0-20200108200545-475eaeb16496 // indirect
@@ -0,0 +1,261 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// GRPCCode mirrors google.golang.org/grpc/codes.Code so the package does
+// not depend on grpc, convert with codes.Code(c)
+type GRPCCode uint32
+
+const (
+	GRPCCodeOK                 GRPCCode = 0
+	GRPCCodeCanceled           GRPCCode = 1
+	GRPCCodeUnknown            GRPCCode = 2
+	GRPCCodeInvalidArgument    GRPCCode = 3
+	GRPCCodeDeadlineExceeded   GRPCCode = 4
+	GRPCCodeNotFound           GRPCCode = 5
+	GRPCCodeAlreadyExists      GRPCCode = 6
+	GRPCCodePermissionDenied   GRPCCode = 7
+	GRPCCodeResourceExhausted  GRPCCode = 8
+	GRPCCodeFailedPrecondition GRPCCode = 9
+	GRPCCodeAborted            GRPCCode = 10
+	GRPCCodeOutOfRange         GRPCCode = 11
+	GRPCCodeUnimplemented      GRPCCode = 12
+	GRPCCodeInternal           GRPCCode = 13
+	GRPCCodeUnavailable        GRPCCode = 14
+	GRPCCodeDataLoss           GRPCCode = 15
+	GRPCCodeUnauthenticated    GRPCCode = 16
+)
+
+// MetadataGRPCCode is the metadata key holding the gRPC code name of
+// errors built by MapGRPCErrors
+const MetadataGRPCCode = "grpc_code"
+
+var grpcCodeNames = [...]string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded", "NotFound",
+	"AlreadyExists", "PermissionDenied", "ResourceExhausted", "FailedPrecondition",
+	"Aborted", "OutOfRange", "Unimplemented", "Internal", "Unavailable", "DataLoss",
+	"Unauthenticated",
+}
+
+// String returns the name used by grpc, e.g. NotFound
+func (c GRPCCode) String() string {
+	if int(c) < len(grpcCodeNames) {
+		return grpcCodeNames[c]
+	}
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// TextCode returns the code name in UPPER_SNAKE_CASE, e.g. NOT_FOUND
+func (c GRPCCode) TextCode() string {
+	name := c.String()
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
+
+// ParseGRPCCode returns the code named name (NotFound or NOT_FOUND)
+func ParseGRPCCode(name string) (GRPCCode, bool) {
+	for i, codeName := range grpcCodeNames {
+		code := GRPCCode(i)
+		if name == codeName || name == code.TextCode() {
+			return code, true
+		}
+	}
+	return GRPCCodeUnknown, false
+}
+
+// HTTPStatus returns the HTTP status used by grpc-gateway for the code
+func (c GRPCCode) HTTPStatus() int {
+	switch c {
+	case GRPCCodeOK:
+		return http.StatusOK
+	case GRPCCodeCanceled:
+		return 499
+	case GRPCCodeInvalidArgument, GRPCCodeFailedPrecondition, GRPCCodeOutOfRange:
+		return http.StatusBadRequest
+	case GRPCCodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case GRPCCodeNotFound:
+		return http.StatusNotFound
+	case GRPCCodeAlreadyExists, GRPCCodeAborted:
+		return http.StatusConflict
+	case GRPCCodePermissionDenied:
+		return http.StatusForbidden
+	case GRPCCodeUnauthenticated:
+		return http.StatusUnauthorized
+	case GRPCCodeResourceExhausted:
+		return http.StatusTooManyRequests
+	case GRPCCodeUnimplemented:
+		return http.StatusNotImplemented
+	case GRPCCodeUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// grpcCodeCategories are the categories of the gRPC codes
+var grpcCodeCategories = map[GRPCCode]Category{
+	GRPCCodeCanceled:           CategoryOperation,
+	GRPCCodeUnknown:            CategoryInternal,
+	GRPCCodeInvalidArgument:    CategoryBadInput,
+	GRPCCodeDeadlineExceeded:   CategoryExternal,
+	GRPCCodeNotFound:           CategoryNotFound,
+	GRPCCodeAlreadyExists:      CategoryConflict,
+	GRPCCodePermissionDenied:   CategoryAuthz,
+	GRPCCodeResourceExhausted:  CategoryRateLimit,
+	GRPCCodeFailedPrecondition: CategoryBadInput,
+	GRPCCodeAborted:            CategoryConflict,
+	GRPCCodeOutOfRange:         CategoryBadInput,
+	GRPCCodeUnimplemented:      CategoryMethodNotAllowed,
+	GRPCCodeInternal:           CategoryInternal,
+	GRPCCodeUnavailable:        CategoryExternal,
+	GRPCCodeDataLoss:           CategoryInternal,
+	GRPCCodeUnauthenticated:    CategoryAuth,
+}
+
+// CategoryGRPCCode returns the gRPC code of the category, Unknown for
+// unknown categories
+func CategoryGRPCCode(category Category) GRPCCode {
+	return DefaultCategoryRegistry.Definition(category).GRPCCode
+}
+
+// GRPCCodeToCategory returns the category of a gRPC code, internal for
+// unknown codes
+func GRPCCodeToCategory(code GRPCCode) Category {
+	if category, ok := grpcCodeCategories[code]; ok {
+		return category
+	}
+	return CategoryInternal
+}
+
+// SetCategoryGRPCCode sets the gRPC code of the category. Meant to be
+// called during startup
+func SetCategoryGRPCCode(category Category, code GRPCCode) {
+	DefaultCategoryRegistry.Update(category, func(def *CategoryDefinition) {
+		def.GRPCCode = code
+	})
+}
+
+// SetGRPCCodeCategory sets the category of a gRPC code. Meant to be
+// called during startup
+func SetGRPCCodeCategory(code GRPCCode, category Category) {
+	grpcCodeCategories[code] = category
+}
+
+// grpcStatusOf reads the code and message of an error exposing
+// GRPCStatus(), as status errors of google.golang.org/grpc do
+func grpcStatusOf(err error) (GRPCCode, string, bool) {
+	method := reflect.ValueOf(err).MethodByName("GRPCStatus")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return 0, "", false
+	}
+
+	status := method.Call(nil)[0]
+	if (status.Kind() == reflect.Pointer || status.Kind() == reflect.Interface) && status.IsNil() {
+		return 0, "", false
+	}
+
+	codeMethod := status.MethodByName("Code")
+	if !codeMethod.IsValid() || codeMethod.Type().NumIn() != 0 || codeMethod.Type().NumOut() != 1 {
+		return 0, "", false
+	}
+
+	var code GRPCCode
+	switch value := codeMethod.Call(nil)[0]; value.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		code = GRPCCode(value.Uint())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		code = GRPCCode(value.Int())
+	default:
+		return 0, "", false
+	}
+
+	var message string
+	if messageMethod := status.MethodByName("Message"); messageMethod.IsValid() &&
+		messageMethod.Type().NumIn() == 0 && messageMethod.Type().NumOut() == 1 &&
+		messageMethod.Type().Out(0).Kind() == reflect.String {
+		message = messageMethod.Call(nil)[0].String()
+	}
+
+	return code, message, true
+}
+
+// findGRPCStatus returns the first gRPC status found in the tree of err
+func findGRPCStatus(err error) (GRPCCode, string, bool) {
+	var (
+		code    GRPCCode
+		message string
+		found   bool
+	)
+	Walk(err, func(node error, _ int) bool {
+		code, message, found = grpcStatusOf(node)
+		return !found
+	})
+	return code, message, found
+}
+
+// MapGRPCErrors maps errors exposing GRPCStatus(), such as the errors
+// returned by grpc clients. The category comes from the code, Code is
+// the matching HTTP status, TextCode the code name (NOT_FOUND) and the
+// message the status message. OK statuses are not mapped
+func MapGRPCErrors(err error) *Error {
+	code, message, ok := findGRPCStatus(err)
+	if !ok || code == GRPCCodeOK {
+		return nil
+	}
+	if message == "" {
+		message = code.String()
+	}
+
+	return Wrap(err, GRPCCodeToCategory(code), message).
+		WithCode(code.HTTPStatus()).
+		WithTextCode(code.TextCode()).
+		WithMetadata(map[string]any{MetadataGRPCCode: code.String()})
+}
+
+// ToGRPCCode returns the gRPC code for err, meant for interceptors
+// converting returned errors with status.Error(codes.Code(c), msg).
+// The code is taken, in order, from a GRPCStatus() in the tree, the
+// grpc_code metadata, the category of the first rich error, or the
+// context errors. nil is OK, anything else Unknown
+func ToGRPCCode(err error) GRPCCode {
+	if err == nil {
+		return GRPCCodeOK
+	}
+
+	if code, _, ok := findGRPCStatus(err); ok {
+		return code
+	}
+
+	var richErr *Error
+	if As(err, &richErr) {
+		if name, ok := richErr.Metadata[MetadataGRPCCode].(string); ok {
+			if code, ok := ParseGRPCCode(name); ok {
+				return code
+			}
+		}
+		if richErr.Category != "" {
+			return CategoryGRPCCode(richErr.Category)
+		}
+	}
+
+	switch {
+	case Is(err, context.Canceled):
+		return GRPCCodeCanceled
+	case Is(err, context.DeadlineExceeded):
+		return GRPCCodeDeadlineExceeded
+	}
+	return GRPCCodeUnknown
+}
@@ -0,0 +1,144 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goliatone/go-errors"
+)
+
+// fakeCode and fakeStatus mimic codes.Code and *status.Status
+type fakeCode uint32
+
+type fakeStatus struct {
+	code    fakeCode
+	message string
+}
+
+func (s *fakeStatus) Code() fakeCode  { return s.code }
+func (s *fakeStatus) Message() string { return s.message }
+
+type fakeStatusError struct {
+	status *fakeStatus
+}
+
+func (e *fakeStatusError) Error() string {
+	return fmt.Sprintf("rpc error: code = %d desc = %s", e.status.code, e.status.message)
+}
+
+func (e *fakeStatusError) GRPCStatus() *fakeStatus { return e.status }
+
+func grpcError(code errors.GRPCCode, message string) error {
+	return &fakeStatusError{status: &fakeStatus{code: fakeCode(code), message: message}}
+}
+
+func TestMapGRPCErrors(t *testing.T) {
+	tests := []struct {
+		code     errors.GRPCCode
+		category errors.Category
+		status   int
+		textCode string
+	}{
+		{errors.GRPCCodeNotFound, errors.CategoryNotFound, http.StatusNotFound, "NOT_FOUND"},
+		{errors.GRPCCodeAlreadyExists, errors.CategoryConflict, http.StatusConflict, "ALREADY_EXISTS"},
+		{errors.GRPCCodePermissionDenied, errors.CategoryAuthz, http.StatusForbidden, "PERMISSION_DENIED"},
+		{errors.GRPCCodeUnauthenticated, errors.CategoryAuth, http.StatusUnauthorized, "UNAUTHENTICATED"},
+		{errors.GRPCCodeResourceExhausted, errors.CategoryRateLimit, http.StatusTooManyRequests, "RESOURCE_EXHAUSTED"},
+		{errors.GRPCCodeUnavailable, errors.CategoryExternal, http.StatusServiceUnavailable, "UNAVAILABLE"},
+		{errors.GRPCCodeDeadlineExceeded, errors.CategoryExternal, http.StatusGatewayTimeout, "DEADLINE_EXCEEDED"},
+		{errors.GRPCCodeInvalidArgument, errors.CategoryBadInput, http.StatusBadRequest, "INVALID_ARGUMENT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			source := grpcError(tt.code, "user 7")
+			mapped := errors.MapGRPCErrors(fmt.Errorf("load profile: %w", source))
+			if mapped == nil {
+				t.Fatal("Expected the status error to be mapped")
+			}
+			if mapped.Category != tt.category {
+				t.Errorf("Expected category %s, got %s", tt.category, mapped.Category)
+			}
+			if mapped.Code != tt.status || mapped.TextCode != tt.textCode {
+				t.Errorf("Expected %d/%s, got %d/%s", tt.status, tt.textCode, mapped.Code, mapped.TextCode)
+			}
+			if mapped.Message != "user 7" {
+				t.Errorf("Expected status message, got %q", mapped.Message)
+			}
+			if errors.ToGRPCCode(mapped) != tt.code {
+				t.Errorf("Expected round trip to %s, got %s", tt.code, errors.ToGRPCCode(mapped))
+			}
+		})
+	}
+
+	if errors.MapGRPCErrors(fmt.Errorf("plain")) != nil {
+		t.Error("Expected errors without GRPCStatus to be ignored")
+	}
+	if errors.MapGRPCErrors(grpcError(errors.GRPCCodeOK, "")) != nil {
+		t.Error("Expected OK statuses to be ignored")
+	}
+	if errors.MapGRPCErrors(&fakeStatusError{}) != nil {
+		t.Error("Expected nil statuses to be ignored")
+	}
+}
+
+func TestToGRPCCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errors.GRPCCode
+	}{
+		{"nil", nil, errors.GRPCCodeOK},
+		{"status", grpcError(errors.GRPCCodeAborted, "retry"), errors.GRPCCodeAborted},
+		{"not found", errors.New("missing", errors.CategoryNotFound), errors.GRPCCodeNotFound},
+		{"validation", errors.NewValidation("invalid"), errors.GRPCCodeInvalidArgument},
+		{"wrapped", fmt.Errorf("handler: %w", errors.New("nope", errors.CategoryAuthz)), errors.GRPCCodePermissionDenied},
+		{"custom category", errors.New("x", errors.Category("billing")), errors.GRPCCodeUnknown},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), errors.GRPCCodeCanceled},
+		{"deadline", context.DeadlineExceeded, errors.GRPCCodeDeadlineExceeded},
+		{"plain", fmt.Errorf("boom"), errors.GRPCCodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.ToGRPCCode(tt.err); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGRPCCodeTable(t *testing.T) {
+	for code := errors.GRPCCodeCanceled; code <= errors.GRPCCodeUnauthenticated; code++ {
+		category := errors.GRPCCodeToCategory(code)
+		if category == "" {
+			t.Errorf("Expected a category for %s", code)
+		}
+		parsed, ok := errors.ParseGRPCCode(code.TextCode())
+		if !ok || parsed != code {
+			t.Errorf("Expected %s to parse back, got %s", code.TextCode(), parsed)
+		}
+		if unknown := errors.DefaultCatalog.UnknownTextCodes(errors.MapGRPCErrors(grpcError(code, "x"))); len(unknown) > 0 {
+			t.Errorf("Expected %s to be in the default catalog, unknown %v", code, unknown)
+		}
+	}
+
+	if got := errors.GRPCCode(99).String(); got != "Code(99)" {
+		t.Errorf("Expected Code(99), got %s", got)
+	}
+
+	errors.SetCategoryGRPCCode("billing", errors.GRPCCodeFailedPrecondition)
+	t.Cleanup(func() { errors.DefaultCategoryRegistry.Unregister("billing") })
+	if got := errors.CategoryGRPCCode("billing"); got != errors.GRPCCodeFailedPrecondition {
+		t.Errorf("Expected custom code, got %s", got)
+	}
+}
+
+func TestDefaultErrorMappers_GRPC(t *testing.T) {
+	mapped := errors.MapToError(grpcError(errors.GRPCCodeNotFound, "order 9"), errors.DefaultErrorMappers())
+	if mapped.Category != errors.CategoryNotFound || mapped.HTTPStatus() != http.StatusNotFound {
+		t.Errorf("Expected not found mapping, got %s/%d", mapped.Category, mapped.HTTPStatus())
+	}
+}
@@ -0,0 +1,340 @@
+package errors
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Handler is an http handler that returns its error instead of writing it
+//
+//	mux.Handle("/users/{id}", errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//		user, err := store.Find(r.PathValue("id"))
+//		if err != nil {
+//			return err
+//		}
+//		return json.NewEncoder(w).Encode(user)
+//	}))
+type Handler func(http.ResponseWriter, *http.Request) error
+
+// ServeHTTP implements http.Handler with the default HTTPOptions
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defaultHTTPOptions().serve(h, w, r)
+}
+
+// DefaultRequestIDHeaders are the request headers read for a request ID
+var DefaultRequestIDHeaders = []string{"X-Request-ID", "X-Correlation-ID"}
+
+// HTTPOptions configure Adapt, Middleware and WriteHTTPError
+type HTTPOptions struct {
+	// Mappers convert returned errors, DefaultErrorMappers when nil
+	Mappers []ErrorMapper
+	// RequestIDHeaders are read, in order, for the request ID and the
+	// first one is set on error responses
+	RequestIDHeaders []string
+	// IncludeStack adds the stack trace to responses, the response
+	// policy of the current Mode still applies
+	IncludeStack bool
+	// Logger logs every written error by severity, nil disables logging
+	Logger *slog.Logger
+	// Negotiator picks the response format from the Accept header,
+	// DefaultNegotiator when nil
+	Negotiator *Negotiator
+}
+
+// HTTPOption configures HTTPOptions
+type HTTPOption func(*HTTPOptions)
+
+// WithMappers sets the mappers applied to returned errors
+func WithMappers(mappers ...ErrorMapper) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.Mappers = mappers
+	}
+}
+
+// WithRequestIDHeaders sets the headers read for the request ID
+func WithRequestIDHeaders(headers ...string) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.RequestIDHeaders = headers
+	}
+}
+
+// WithStackInResponse includes stack traces in responses
+func WithStackInResponse(include bool) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.IncludeStack = include
+	}
+}
+
+// WithLogger logs written errors with LogBySeverity
+func WithLogger(logger *slog.Logger) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.Logger = logger
+	}
+}
+
+// WithFormatters sets the formats offered to clients, the first one is
+// used when the Accept header matches none
+func WithFormatters(formatters ...ResponseFormatter) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.Negotiator = NewNegotiator(formatters...)
+	}
+}
+
+func newHTTPOptions(opts []HTTPOption) *HTTPOptions {
+	o := &HTTPOptions{
+		Mappers:          DefaultErrorMappers(),
+		RequestIDHeaders: DefaultRequestIDHeaders,
+		Negotiator:       DefaultNegotiator(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// defaultHTTPOptions are shared by Handler and WriteHTTPError calls
+// without options, built on first use
+var defaultHTTPOptions = sync.OnceValue(func() *HTTPOptions {
+	return newHTTPOptions(nil)
+})
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a context carrying the request ID
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored by ContextWithRequestID
+// or Middleware, empty if none
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func (o *HTTPOptions) requestID(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	if id := RequestIDFromContext(r.Context()); id != "" {
+		return id
+	}
+	for _, header := range o.RequestIDHeaders {
+		if id := r.Header.Get(header); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// Adapt converts h into an http.Handler. Returned errors and panics are
+// mapped, stamped with the request ID and written with WriteHTTPError
+func Adapt(h Handler, opts ...HTTPOption) http.Handler {
+	o := newHTTPOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o.serve(h, w, r)
+	})
+}
+
+func (o *HTTPOptions) serve(h Handler, w http.ResponseWriter, r *http.Request) {
+	rw := &responseWriter{ResponseWriter: w}
+	defer o.recover(rw, r)
+
+	if err := h(rw, r); err != nil {
+		o.write(rw, r, err)
+	}
+}
+
+// Middleware stores the request ID found in the request headers in the
+// context and writes an error response for panics of next
+func Middleware(opts ...HTTPOption) func(http.Handler) http.Handler {
+	o := newHTTPOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if id := o.requestID(r); id != "" && RequestIDFromContext(r.Context()) == "" {
+				r = r.WithContext(ContextWithRequestID(r.Context(), id))
+			}
+
+			rw := &responseWriter{ResponseWriter: w}
+			defer o.recover(rw, r)
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// WriteHTTPError maps err and writes it in the format negotiated from
+// the Accept header, JSON by default
+func WriteHTTPError(w http.ResponseWriter, r *http.Request, err error, opts ...HTTPOption) {
+	if len(opts) == 0 {
+		defaultHTTPOptions().write(w, r, err)
+		return
+	}
+	newHTTPOptions(opts).write(w, r, err)
+}
+
+// FromPanic converts a recovered panic value into an internal error
+// with a stack trace. Errors passed to panic are wrapped
+func FromPanic(recovered any) *Error {
+	var e *Error
+	if err, ok := recovered.(error); ok {
+		e = Wrap(err, CategoryInternal, "panic recovered")
+	} else {
+		e = New(fmt.Sprintf("panic recovered: %v", recovered), CategoryInternal)
+	}
+	return e.
+		WithCode(http.StatusInternalServerError).
+		WithTextCode(TextCodeInternalError).
+		WithSeverity(SeverityCritical).
+		WithStackTrace()
+}
+
+func (o *HTTPOptions) recover(w *responseWriter, r *http.Request) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	if recovered == http.ErrAbortHandler {
+		panic(recovered)
+	}
+	o.write(w, r, FromPanic(recovered))
+}
+
+func (o *HTTPOptions) write(w http.ResponseWriter, r *http.Request, err error) {
+	o.writeWith(w, r, err, nil)
+}
+
+// writeWith writes err with formatter, negotiated when nil
+func (o *HTTPOptions) writeWith(w http.ResponseWriter, r *http.Request, err error, formatter ResponseFormatter) {
+	richErr := MapToError(err, o.Mappers)
+	if richErr == nil {
+		return
+	}
+	// As resolves a MultiError to its BaseError, the causes hold field
+	// errors the client needs
+	var multiErr *MultiError
+	if As(err, &multiErr) && multiErr.BaseError == richErr {
+		richErr = richErr.Clone()
+		richErr.ValidationErrors = multiErr.AllValidationErrors()
+	}
+	if richErr.RequestID == "" {
+		if id := o.requestID(r); id != "" {
+			richErr = richErr.Clone().WithRequestID(id)
+		}
+	}
+
+	if o.Logger != nil {
+		LogBySeverity(o.Logger, richErr)
+	}
+
+	// the handler already started the response, the error can only be logged
+	if rw, ok := w.(*responseWriter); ok && rw.written {
+		return
+	}
+
+	status := richErr.HTTPStatus()
+	response := richErr.ToErrorResponse(o.IncludeStack, richErr.StackTrace)
+
+	if localizer != nil {
+		locale, supported := requestLocale(r)
+		w.Header().Add("Vary", "Accept-Language")
+		translated := localizeResponse(response.Error, richErr, locale)
+		if locale != "" && (supported || translated) {
+			w.Header().Set("Content-Language", locale)
+		}
+	}
+
+	header := w.Header()
+	if response.Error.RequestID != "" && len(o.RequestIDHeaders) > 0 {
+		header.Set(o.RequestIDHeaders[0], response.Error.RequestID)
+	}
+	if seconds := retryAfterSeconds(err); seconds > 0 {
+		header.Set("Retry-After", strconv.Itoa(seconds))
+	}
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Cache-Control", "no-store")
+
+	if formatter == nil {
+		negotiator := o.Negotiator
+		if negotiator == nil {
+			negotiator = DefaultNegotiator()
+		}
+		header.Add("Vary", "Accept")
+		formatter = negotiator.Negotiate(requestHeader(r, "Accept"))
+	}
+	_ = writeFormatted(w, r, formatter, response, status)
+}
+
+// requestHeader returns the header value of r, empty for a nil request
+func requestHeader(r *http.Request, name string) string {
+	if r == nil {
+		return ""
+	}
+	return r.Header.Get(name)
+}
+
+// retryAfterSeconds returns the delay of a retryable error, rounded up
+func retryAfterSeconds(err error) int {
+	var retryErr *RetryableError
+	if !As(err, &retryErr) || !retryErr.IsRetryable() {
+		return 0
+	}
+	return int(math.Ceil(retryErr.RetryDelay(0).Seconds()))
+}
+
+// responseWriter records whether the response was started. It forwards
+// http.Flusher, http.Hijacker and io.ReaderFrom to the wrapped writer so
+// streaming and websocket handlers keep working
+type responseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered data to the client, a no-op when the wrapped
+// writer does not support flushing
+func (w *responseWriter) Flush() {
+	w.written = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack takes over the connection, see http.Hijacker
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.written = true
+	}
+	return conn, rw, err
+}
+
+// ReadFrom copies src to the response, using the io.ReaderFrom of the
+// wrapped writer when it has one
+func (w *responseWriter) ReadFrom(src io.Reader) (int64, error) {
+	w.written = true
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		return rf.ReadFrom(src)
+	}
+	return io.Copy(w.ResponseWriter, src)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
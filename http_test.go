@@ -0,0 +1,303 @@
+package errors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-errors"
+)
+
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) *errors.Error {
+	t.Helper()
+	var response struct {
+		Error *errors.Error `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", w.Body.String(), err)
+	}
+	return response.Error
+}
+
+func TestCategoryHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err  *errors.Error
+		want int
+	}{
+		{errors.New("x", errors.CategoryNotFound), http.StatusNotFound},
+		{errors.New("x", errors.CategoryValidation), http.StatusBadRequest},
+		{errors.New("x", errors.CategoryExternal), http.StatusBadGateway},
+		{errors.New("x", errors.Category("custom")), http.StatusInternalServerError},
+		{errors.New("x", errors.CategoryNotFound).WithCode(http.StatusGone), http.StatusGone},
+		{errors.New("x", errors.CategoryNotFound).WithCode(42), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if got := tt.err.HTTPStatus(); got != tt.want {
+			t.Errorf("Expected %d for %s/%d, got %d", tt.want, tt.err.Category, tt.err.Code, got)
+		}
+	}
+}
+
+func TestAdapt_WritesError(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	h := errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("user 42 missing", errors.CategoryNotFound).WithTextCode("USER_NOT_FOUND")
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	r.Header.Set("X-Request-ID", "req-123")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Expected JSON content type, got %q", got)
+	}
+	if got := w.Header().Get("X-Request-ID"); got != "req-123" {
+		t.Errorf("Expected request ID header, got %q", got)
+	}
+
+	e := decodeResponse(t, w)
+	if e.Code != http.StatusNotFound {
+		t.Errorf("Expected code 404 in body, got %d", e.Code)
+	}
+	if e.TextCode != "USER_NOT_FOUND" {
+		t.Errorf("Expected text code USER_NOT_FOUND, got %s", e.TextCode)
+	}
+	if e.RequestID != "req-123" {
+		t.Errorf("Expected request ID req-123, got %q", e.RequestID)
+	}
+	if e.Message != errors.CategoryPublicMessage(errors.CategoryNotFound) {
+		t.Errorf("Expected public message, got %q", e.Message)
+	}
+}
+
+func TestAdapt_MultiErrorValidation(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	h := errors.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+		return &errors.MultiError{
+			BaseError: errors.New("signup failed", errors.CategoryValidation),
+			Causes: []error{
+				errors.NewValidation("invalid", errors.FieldError{Field: "email", Message: "required"}),
+				errors.NewValidation("invalid", errors.FieldError{Field: "name", Message: "required"}),
+			},
+		}
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/signup", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+	if fields := decodeResponse(t, w).ValidationErrors; len(fields) != 2 {
+		t.Errorf("Expected the field errors of every cause, got %v", fields)
+	}
+}
+
+func TestAdapt_NoError(t *testing.T) {
+	h := errors.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("Expected untouched 204 response, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestAdapt_Mappers(t *testing.T) {
+	errTimeout := stderrors.New("db timeout")
+	mapper := func(err error) *errors.Error {
+		if stderrors.Is(err, errTimeout) {
+			return errors.Wrap(err, errors.CategoryExternal, "database unavailable").WithCode(http.StatusServiceUnavailable)
+		}
+		return nil
+	}
+
+	h := errors.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+		return errTimeout
+	}, errors.WithMappers(mapper))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
+	}
+
+	h = errors.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+		return stderrors.New("boom")
+	})
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected unmapped errors to be 500, got %d", w.Code)
+	}
+}
+
+func TestAdapt_RecoversPanic(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	h := errors.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+		panic("nil map write")
+	}, errors.WithLogger(logger))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+	e := decodeResponse(t, w)
+	if strings.Contains(e.Message, "nil map") {
+		t.Errorf("Expected panic value to stay out of the response, got %q", e.Message)
+	}
+	if e.RequestID == "" {
+		t.Error("Expected a correlation ID for the server error")
+	}
+	if !strings.Contains(logs.String(), "nil map write") {
+		t.Errorf("Expected panic to be logged, got %q", logs.String())
+	}
+}
+
+func TestAdapt_ResponseStarted(t *testing.T) {
+	h := errors.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusAccepted)
+		return stderrors.New("late failure")
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusAccepted || w.Body.Len() != 0 {
+		t.Errorf("Expected the started response to be left alone, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestAdapt_RetryAfter(t *testing.T) {
+	h := errors.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.NewRetryable("slow down", errors.CategoryRateLimit).WithRetryDelay(1500 * time.Millisecond)
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After 2, got %q", got)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var seen string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = errors.RequestIDFromContext(r.Context())
+		panic(stderrors.New("handler exploded"))
+	})
+
+	h := errors.Middleware(errors.WithRequestIDHeaders("X-Trace-ID"))(next)
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("X-Trace-ID", "trace-9")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if seen != "trace-9" {
+		t.Errorf("Expected request ID in context, got %q", seen)
+	}
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+	if got := w.Header().Get("X-Trace-ID"); got != "trace-9" {
+		t.Errorf("Expected request ID header, got %q", got)
+	}
+	if e := decodeResponse(t, w); e.RequestID != "trace-9" {
+		t.Errorf("Expected request ID trace-9, got %q", e.RequestID)
+	}
+}
+
+func TestMiddleware_AbortHandler(t *testing.T) {
+	h := errors.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("Expected ErrAbortHandler to propagate, got %v", recovered)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestMiddleware_ForwardsWriterInterfaces(t *testing.T) {
+	h := errors.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("Expected the writer to implement http.Flusher")
+		}
+		if _, ok := w.(io.ReaderFrom); !ok {
+			t.Fatal("Expected the writer to implement io.ReaderFrom")
+		}
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("Expected the writer to implement http.Hijacker")
+		}
+		if _, _, err := hijacker.Hijack(); !stderrors.Is(err, http.ErrNotSupported) {
+			t.Errorf("Expected ErrNotSupported from a recorder, got %v", err)
+		}
+
+		_, _ = io.Copy(w, strings.NewReader("data: 1\n\n"))
+		flusher.Flush()
+		panic("after the stream started")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if !w.Flushed {
+		t.Error("Expected Flush to reach the recorder")
+	}
+	if w.Body.String() != "data: 1\n\n" {
+		t.Errorf("Expected only the streamed body, got %q", w.Body.String())
+	}
+}
+
+func TestWriteHTTPError_Head(t *testing.T) {
+	w := httptest.NewRecorder()
+	errors.WriteHTTPError(w, httptest.NewRequest(http.MethodHead, "/", nil), errors.New("missing", errors.CategoryNotFound))
+	if w.Code != http.StatusNotFound || w.Body.Len() != 0 {
+		t.Errorf("Expected 404 without body, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestWriteHTTPError_NilRequest(t *testing.T) {
+	withLocalizer(t, newTestCatalog(t))
+
+	w := httptest.NewRecorder()
+	errors.WriteHTTPError(w, nil, errors.New("missing", errors.CategoryNotFound))
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("Expected a 404 JSON response, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if decodeResponse(t, w).Category != errors.CategoryNotFound {
+		t.Error("Expected the error in the body")
+	}
+	if got := errors.RequestLocale(nil); got != "" {
+		t.Errorf("Expected no locale for a nil request, got %q", got)
+	}
+}
@@ -0,0 +1,556 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Localizer translates messages keyed by text code or validation rule code
+type Localizer interface {
+	// Localize returns the message for key in locale with the named
+	// params applied, false if neither the locale nor its fallbacks
+	// define the key
+	Localize(locale, key string, params map[string]any) (string, bool)
+}
+
+var localizer Localizer
+
+// SetLocalizer sets the Localizer used by LocalizedResponse and
+// LocalizedValidationMap. Meant to be called during startup
+func SetLocalizer(l Localizer) {
+	localizer = l
+}
+
+// GetLocalizer returns the configured Localizer, nil if none
+func GetLocalizer() Localizer {
+	return localizer
+}
+
+// CategoryMessageKey is the key used to localize the generic public
+// message of a category, e.g. "category.not_found"
+func CategoryMessageKey(category Category) string {
+	return "category." + category.String()
+}
+
+// pluralCategories are the CLDR plural categories accepted in catalogs
+var pluralCategories = map[string]bool{
+	"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+}
+
+// MessageCatalog is a Localizer backed by per locale message files.
+//
+// Keys are text codes (USER_NOT_FOUND), validation rule codes
+// (validation_required) or category keys (category.not_found). Nested
+// tables are flattened with dots. A table with only plural categories
+// (zero, one, two, few, many, other) is a plural message selected by
+// the "count" param. Messages reference params as {name}.
+//
+//	{
+//	  "USER_NOT_FOUND": "Utilisateur {user_id} introuvable",
+//	  "CART_LIMIT": {"one": "{count} article maximum", "other": "{count} articles maximum"}
+//	}
+type MessageCatalog struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]map[string]string
+	fallbacks     map[string][]string
+}
+
+// NewMessageCatalog creates an empty catalog. defaultLocale ends every
+// fallback chain
+func NewMessageCatalog(defaultLocale string) *MessageCatalog {
+	return &MessageCatalog{
+		defaultLocale: normalizeLocale(defaultLocale),
+		messages:      make(map[string]map[string]map[string]string),
+		fallbacks:     make(map[string][]string),
+	}
+}
+
+// Add merges messages into locale, values are strings, plural tables or
+// nested tables
+func (c *MessageCatalog) Add(locale string, messages map[string]any) error {
+	flat := make(map[string]map[string]string)
+	if err := flattenMessages("", messages, flat); err != nil {
+		return fmt.Errorf("i18n %s: %w", locale, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	locale = normalizeLocale(locale)
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]map[string]string)
+	}
+	for key, forms := range flat {
+		c.messages[locale][key] = forms
+	}
+	return nil
+}
+
+// LoadJSON adds the messages of a JSON document to locale
+func (c *MessageCatalog) LoadJSON(locale string, data []byte) error {
+	var messages map[string]any
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("i18n %s: %w", locale, err)
+	}
+	return c.Add(locale, messages)
+}
+
+// LoadTOML adds the messages of a TOML document to locale
+func (c *MessageCatalog) LoadTOML(locale string, data []byte) error {
+	var messages map[string]any
+	if err := toml.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("i18n %s: %w", locale, err)
+	}
+	return c.Add(locale, messages)
+}
+
+// LoadFile adds a .json or .toml file, the locale is the file name
+// without extension, e.g. locales/pt-BR.json
+func (c *MessageCatalog) LoadFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return c.load(filepath.Base(filename), data)
+}
+
+// LoadFS adds every .json and .toml file found in dir, works with embed.FS
+func (c *MessageCatalog) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := c.load(entry.Name(), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *MessageCatalog) load(name string, data []byte) error {
+	ext := path.Ext(name)
+	locale := strings.TrimSuffix(name, ext)
+	switch ext {
+	case ".json":
+		return c.LoadJSON(locale, data)
+	case ".toml":
+		return c.LoadTOML(locale, data)
+	default:
+		return fmt.Errorf("i18n: unsupported file %q, use .json or .toml", name)
+	}
+}
+
+// SetFallbacks sets the locales tried after locale, before the default
+// locale. Without explicit fallbacks, pt-BR falls back to pt
+func (c *MessageCatalog) SetFallbacks(locale string, fallbacks ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	normalized := make([]string, len(fallbacks))
+	for i, fallback := range fallbacks {
+		normalized[i] = normalizeLocale(fallback)
+	}
+	c.fallbacks[normalizeLocale(locale)] = normalized
+}
+
+// DefaultLocale returns the locale ending every fallback chain
+func (c *MessageCatalog) DefaultLocale() string {
+	return c.defaultLocale
+}
+
+// Locales returns the locales with messages, sorted
+func (c *MessageCatalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// FallbackChain returns the locales tried for locale, in order
+func (c *MessageCatalog) FallbackChain(locale string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fallbackChain(normalizeLocale(locale))
+}
+
+func (c *MessageCatalog) fallbackChain(locale string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(l string) {
+		if l != "" && !seen[l] {
+			seen[l] = true
+			chain = append(chain, l)
+		}
+	}
+
+	add(locale)
+	if fallbacks, ok := c.fallbacks[locale]; ok {
+		for _, fallback := range fallbacks {
+			add(fallback)
+		}
+	} else {
+		for tag := locale; strings.Contains(tag, "-"); {
+			tag = tag[:strings.LastIndex(tag, "-")]
+			add(tag)
+		}
+	}
+	add(c.defaultLocale)
+	return chain
+}
+
+// Localize implements Localizer
+func (c *MessageCatalog) Localize(locale, key string, params map[string]any) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, candidate := range c.fallbackChain(normalizeLocale(locale)) {
+		forms, ok := c.messages[candidate][key]
+		if !ok {
+			continue
+		}
+
+		template, ok := forms[pluralCategoryFor(candidate, params)]
+		if !ok {
+			template = forms["other"]
+		}
+		return interpolate(template, params), true
+	}
+	return "", false
+}
+
+func flattenMessages(prefix string, messages map[string]any, out map[string]map[string]string) error {
+	for key, value := range messages {
+		fullKey := prefixKey(prefix, key)
+		switch v := value.(type) {
+		case string:
+			out[fullKey] = map[string]string{"other": v}
+		case map[string]any:
+			if forms, ok := pluralForms(v); ok {
+				out[fullKey] = forms
+				continue
+			}
+			if err := flattenMessages(fullKey, v, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %s must be a string or a table, got %T", fullKey, value)
+		}
+	}
+	return nil
+}
+
+// pluralForms returns the table as plural forms if every key is a plural
+// category with a string value
+func pluralForms(table map[string]any) (map[string]string, bool) {
+	if len(table) == 0 {
+		return nil, false
+	}
+	forms := make(map[string]string, len(table))
+	for key, value := range table {
+		text, ok := value.(string)
+		if !ok || !pluralCategories[key] {
+			return nil, false
+		}
+		forms[key] = text
+	}
+	return forms, true
+}
+
+var paramPattern = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_.]*)\}`)
+
+// interpolate replaces {name} with the param value, unknown params are kept
+func interpolate(template string, params map[string]any) string {
+	if len(params) == 0 {
+		return template
+	}
+	return paramPattern.ReplaceAllStringFunc(template, func(match string) string {
+		if value, ok := params[match[1:len(match)-1]]; ok {
+			return fmt.Sprint(value)
+		}
+		return match
+	})
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// PluralRule returns the CLDR plural category of n
+type PluralRule func(n int64) string
+
+var pluralRules = map[string]PluralRule{}
+
+func init() {
+	oneOther := func(n int64) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+	zeroOrOne := func(n int64) string {
+		if n == 0 || n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+	other := func(int64) string { return "other" }
+	slavic := func(n int64) string {
+		switch mod10, mod100 := n%10, n%100; {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	}
+
+	for _, lang := range []string{"en", "de", "nl", "sv", "da", "nb", "no", "fi", "et", "it", "es", "el", "hu", "tr", "ca", "bg"} {
+		pluralRules[lang] = oneOther
+	}
+	for _, lang := range []string{"fr", "pt", "hi"} {
+		pluralRules[lang] = zeroOrOne
+	}
+	for _, lang := range []string{"ja", "zh", "ko", "vi", "th", "id", "ms"} {
+		pluralRules[lang] = other
+	}
+	for _, lang := range []string{"ru", "uk", "be"} {
+		pluralRules[lang] = slavic
+	}
+	pluralRules["pl"] = func(n int64) string {
+		if n == 1 {
+			return "one"
+		}
+		if category := slavic(n); category == "few" {
+			return category
+		}
+		return "many"
+	}
+	pluralRules["cs"] = func(n int64) string {
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		default:
+			return "other"
+		}
+	}
+	pluralRules["sk"] = pluralRules["cs"]
+	pluralRules["ar"] = func(n int64) string {
+		switch mod100 := n % 100; {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case mod100 >= 3 && mod100 <= 10:
+			return "few"
+		case mod100 >= 11:
+			return "many"
+		default:
+			return "other"
+		}
+	}
+}
+
+// RegisterPluralRule sets the plural rule of a language such as "cy".
+// Meant to be called during startup
+func RegisterPluralRule(language string, rule PluralRule) {
+	pluralRules[normalizeLocale(language)] = rule
+}
+
+// PluralCategory returns the CLDR plural category of n in locale,
+// languages without a rule use the English one/other rule
+func PluralCategory(locale string, n int64) string {
+	locale = normalizeLocale(locale)
+	language, _, _ := strings.Cut(locale, "-")
+	if rule, ok := pluralRules[locale]; ok {
+		return rule(n)
+	}
+	if rule, ok := pluralRules[language]; ok {
+		return rule(n)
+	}
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralCategoryFor picks the plural category from the count param,
+// non integer or missing counts use "other"
+func pluralCategoryFor(locale string, params map[string]any) string {
+	var n int64
+	switch count := params["count"].(type) {
+	case int:
+		n = int64(count)
+	case int8:
+		n = int64(count)
+	case int16:
+		n = int64(count)
+	case int32:
+		n = int64(count)
+	case int64:
+		n = count
+	case uint:
+		n = int64(count)
+	case uint8:
+		n = int64(count)
+	case uint16:
+		n = int64(count)
+	case uint32:
+		n = int64(count)
+	case uint64:
+		n = int64(count)
+	case float64:
+		if count != float64(int64(count)) {
+			return "other"
+		}
+		n = int64(count)
+	default:
+		return "other"
+	}
+	if n < 0 {
+		n = -n
+	}
+	return PluralCategory(locale, n)
+}
+
+// LocalizeFieldError returns the message of fieldErr in locale, using
+// its Code as key and its Params plus field, label and value as params.
+// The original message is returned when there is no translation
+func LocalizeFieldError(fieldErr FieldError, locale string) string {
+	if localizer == nil || fieldErr.Code == "" {
+		return fieldErr.Message
+	}
+	if message, ok := localizeField(fieldErr, locale, fieldErrorParams(fieldErr, locale)); ok {
+		return message
+	}
+	return fieldErr.Message
+}
+
+func localizeField(fieldErr FieldError, locale string, params map[string]any) (string, bool) {
+	if localizer == nil || fieldErr.Code == "" {
+		return "", false
+	}
+	return localizer.Localize(locale, fieldErr.Code, params)
+}
+
+// fieldErrorParams are the named params available to field messages
+func fieldErrorParams(fieldErr FieldError, locale string) map[string]any {
+	params := make(map[string]any, len(fieldErr.Params)+3)
+	for k, v := range fieldErr.Params {
+		params[k] = v
+	}
+	params["field"] = fieldErr.Field
+	params["label"] = fieldErr.Label
+	if fieldErr.Label == "" {
+		params["label"] = DefaultFieldLabels.Label(fieldErr.Field, locale)
+	}
+	if fieldErr.Value != nil {
+		params["value"] = fieldErr.Value
+	}
+	return params
+}
+
+// LocalizedValidationMap is ValidationMap with messages localized by
+// rule code, see LocalizeFieldError
+func (e *Error) LocalizedValidationMap(locale string) map[string]string {
+	return localizedFieldMessages(e.validationFieldsWithPath(""), locale)
+}
+
+// LocalizedValidationMap is ValidationMap with messages localized by
+// rule code, see LocalizeFieldError
+func (m *MultiError) LocalizedValidationMap(locale string) map[string]string {
+	return localizedFieldMessages(m.validationFields(), locale)
+}
+
+func localizedFieldMessages(fields map[string]FieldError, locale string) map[string]string {
+	result := make(map[string]string, len(fields))
+	for k, fieldErr := range fields {
+		result[k] = LocalizeFieldError(fieldErr, locale)
+	}
+	return result
+}
+
+// LocalizedResponse maps err with the default mappers and returns its
+// error response with the public message and validation messages
+// translated to locale. The text code is tried first, then the category
+// key when the error has no explicit public message. Error metadata is
+// available as named params
+func LocalizedResponse(err error, locale string) ErrorResponse {
+	response, _ := localizedResponse(err, locale)
+	return response
+}
+
+// localizedResponse is LocalizedResponse reporting whether the message
+// was translated
+func localizedResponse(err error, locale string) (ErrorResponse, bool) {
+	richErr := MapToError(err, DefaultErrorMappers())
+	if richErr == nil {
+		return ErrorResponse{}, false
+	}
+
+	response := richErr.ToErrorResponse(false, nil)
+	translated := localizeResponse(response.Error, richErr, locale)
+	return response, translated
+}
+
+// localizeResponse translates the client facing messages of response,
+// original is the error the response was built from. Params come from
+// the response so redacted metadata never reaches a template. Reports
+// whether the message was translated
+func localizeResponse(response, original *Error, locale string) bool {
+	if localizer == nil || response == nil {
+		return false
+	}
+
+	params := response.Metadata
+	message, ok := "", false
+	if original.TextCode != "" {
+		message, ok = localizer.Localize(locale, original.TextCode, params)
+	}
+	if !ok && original.PublicMessage == "" {
+		message, ok = localizer.Localize(locale, CategoryMessageKey(original.Category), params)
+	}
+	if ok {
+		// when the policy exposes the internal message it is kept and the
+		// translation goes to the public message
+		if GetResponsePolicy(CurrentMode()).rulesFor(original).ExposeMessage {
+			response.PublicMessage = message
+		} else {
+			response.Message = message
+		}
+	}
+
+	for i, fieldErr := range response.ValidationErrors {
+		response.ValidationErrors[i].Message = LocalizeFieldError(fieldErr, locale)
+	}
+	return ok
+}
@@ -0,0 +1,266 @@
+package errors_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/goliatone/go-errors"
+)
+
+func newTestCatalog(t *testing.T) *errors.MessageCatalog {
+	t.Helper()
+
+	catalog := errors.NewMessageCatalog("en")
+	for _, file := range []string{"en.json", "fr.toml", "pt-BR.json"} {
+		if err := catalog.LoadFile("testdata/locales/" + file); err != nil {
+			t.Fatalf("Failed to load %s: %v", file, err)
+		}
+	}
+	return catalog
+}
+
+func withLocalizer(t *testing.T, l errors.Localizer) {
+	t.Helper()
+	previous := errors.GetLocalizer()
+	errors.SetLocalizer(l)
+	t.Cleanup(func() { errors.SetLocalizer(previous) })
+}
+
+func TestMessageCatalog_Localize(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		params map[string]any
+		want   string
+		found  bool
+	}{
+		{"json", "en", "USER_NOT_FOUND", map[string]any{"user_id": 42}, "User 42 was not found", true},
+		{"toml", "fr", "USER_NOT_FOUND", map[string]any{"user_id": 42}, "Utilisateur 42 introuvable", true},
+		{"nested key", "fr", "category.not_found", nil, "Ressource introuvable", true},
+		{"region", "pt-BR", "USER_NOT_FOUND", map[string]any{"user_id": 7}, "Usuário 7 não encontrado", true},
+		{"region fallback", "fr-CA", "USER_NOT_FOUND", map[string]any{"user_id": 1}, "Utilisateur 1 introuvable", true},
+		{"default fallback", "pt-BR", "validation_required", map[string]any{"field": "email"}, "email is required", true},
+		{"normalized locale", "PT_br", "USER_NOT_FOUND", map[string]any{"user_id": 7}, "Usuário 7 não encontrado", true},
+		{"missing param kept", "en", "USER_NOT_FOUND", nil, "User {user_id} was not found", true},
+		{"unknown key", "en", "NOPE", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := catalog.Localize(tt.locale, tt.key, tt.params)
+			if ok != tt.found {
+				t.Fatalf("Expected found %t, got %t", tt.found, ok)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMessageCatalog_Plurals(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	tests := []struct {
+		locale string
+		count  any
+		want   string
+	}{
+		{"en", 1, "You can add 1 item"},
+		{"en", 0, "You can add 0 items"},
+		{"en", int64(5), "You can add 5 items"},
+		{"en", 1.0, "You can add 1 item"},
+		{"fr", 0, "Vous pouvez ajouter 0 article"},
+		{"fr", 1, "Vous pouvez ajouter 1 article"},
+		{"fr", 2, "Vous pouvez ajouter 2 articles"},
+	}
+
+	for _, tt := range tests {
+		got, _ := catalog.Localize(tt.locale, "CART_LIMIT", map[string]any{"count": tt.count})
+		if got != tt.want {
+			t.Errorf("%s/%v: expected %q, got %q", tt.locale, tt.count, tt.want, got)
+		}
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      int64
+		want   string
+	}{
+		{"en", 1, "one"},
+		{"en", 2, "other"},
+		{"ru", 1, "one"},
+		{"ru", 3, "few"},
+		{"ru", 11, "many"},
+		{"ru", 21, "one"},
+		{"pl", 22, "few"},
+		{"pl", 21, "many"},
+		{"cs", 4, "few"},
+		{"ja", 1, "other"},
+		{"ar", 0, "zero"},
+		{"ar", 2, "two"},
+		{"ar", 11, "many"},
+		{"xx", 1, "one"},
+	}
+
+	for _, tt := range tests {
+		if got := errors.PluralCategory(tt.locale, tt.n); got != tt.want {
+			t.Errorf("%s/%d: expected %s, got %s", tt.locale, tt.n, tt.want, got)
+		}
+	}
+}
+
+func TestMessageCatalog_Fallbacks(t *testing.T) {
+	catalog := newTestCatalog(t)
+	catalog.SetFallbacks("ca", "fr")
+
+	chain := catalog.FallbackChain("ca")
+	want := []string{"ca", "fr", "en"}
+	if len(chain) != len(want) {
+		t.Fatalf("Expected chain %v, got %v", want, chain)
+	}
+	for i := range want {
+		if chain[i] != want[i] {
+			t.Errorf("Expected chain %v, got %v", want, chain)
+		}
+	}
+
+	got, _ := catalog.Localize("ca", "validation_required", map[string]any{"field": "nom"})
+	if got != "nom est obligatoire" {
+		t.Errorf("Expected french fallback, got %q", got)
+	}
+}
+
+func TestMessageCatalog_LoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/de.json":   {Data: []byte(`{"USER_NOT_FOUND": "Benutzer {user_id} nicht gefunden"}`)},
+		"locales/es.toml":   {Data: []byte(`USER_NOT_FOUND = "Usuario {user_id} no encontrado"`)},
+		"locales/README.md": {Data: []byte("ignored")},
+	}
+
+	catalog := errors.NewMessageCatalog("en")
+	if err := catalog.LoadFS(fsys, "locales"); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	locales := catalog.Locales()
+	if len(locales) != 2 || locales[0] != "de" || locales[1] != "es" {
+		t.Errorf("Expected [de es], got %v", locales)
+	}
+
+	if err := catalog.LoadJSON("it", []byte(`{"BAD": 1}`)); err == nil {
+		t.Error("Expected error for non string message")
+	}
+}
+
+func TestLocalizedResponse(t *testing.T) {
+	withLocalizer(t, newTestCatalog(t))
+
+	err := errors.New("user lookup failed", errors.CategoryNotFound).
+		WithCode(404).
+		WithTextCode("USER_NOT_FOUND").
+		WithMetadata(map[string]any{"user_id": 42})
+
+	response := errors.LocalizedResponse(err, "fr")
+	if response.Error.Message != "Utilisateur 42 introuvable" {
+		t.Errorf("Expected french message, got %q", response.Error.Message)
+	}
+	if err.Message != "user lookup failed" {
+		t.Errorf("Expected original message unchanged, got %q", err.Message)
+	}
+
+	generic := errors.New("row missing", errors.CategoryNotFound)
+	response = errors.LocalizedResponse(generic, "fr")
+	if response.Error.Message != "Ressource introuvable" {
+		t.Errorf("Expected category message, got %q", response.Error.Message)
+	}
+
+	explicit := errors.New("row missing", errors.CategoryNotFound).WithPublicMessage("Gone")
+	response = errors.LocalizedResponse(explicit, "fr")
+	if response.Error.Message != "Gone" {
+		t.Errorf("Expected explicit public message, got %q", response.Error.Message)
+	}
+}
+
+func TestLocalizedResponse_RedactedParams(t *testing.T) {
+	catalog := errors.NewMessageCatalog("en")
+	if err := catalog.LoadJSON("en", []byte(`{"RESET_FAILED": "Reset failed for {token}"}`)); err != nil {
+		t.Fatalf("Failed to load catalog: %v", err)
+	}
+	withLocalizer(t, catalog)
+
+	previous := errors.GetRedactionPolicy()
+	errors.SetRedactionPolicy(errors.RedactionPolicy{Client: errors.DefaultRedactor()})
+	t.Cleanup(func() { errors.SetRedactionPolicy(previous) })
+
+	err := errors.New("reset failed", errors.CategoryBadInput).
+		WithTextCode("RESET_FAILED").
+		WithMetadata(map[string]any{"token": "s3cr3t"})
+
+	response := errors.LocalizedResponse(err, "en")
+	if response.Error.Message != "Reset failed for [REDACTED]" {
+		t.Errorf("Expected redacted param, got %q", response.Error.Message)
+	}
+}
+
+func TestLocalizedResponse_DevelopmentKeepsMessage(t *testing.T) {
+	withMode(t, errors.ModeDevelopment)
+	withLocalizer(t, newTestCatalog(t))
+
+	err := errors.New("user lookup failed", errors.CategoryNotFound).
+		WithTextCode("USER_NOT_FOUND").
+		WithMetadata(map[string]any{"user_id": 42})
+
+	response := errors.LocalizedResponse(err, "fr")
+	if response.Error.Message != "user lookup failed" {
+		t.Errorf("Expected internal message kept, got %q", response.Error.Message)
+	}
+	if response.Error.PublicMessage != "Utilisateur 42 introuvable" {
+		t.Errorf("Expected french public message, got %q", response.Error.PublicMessage)
+	}
+}
+
+func TestLocalizedValidationMap(t *testing.T) {
+	withLocalizer(t, newTestCatalog(t))
+
+	type form struct {
+		Name  string
+		Email string
+	}
+	f := form{Name: "ab"}
+	verr := validation.ValidateStruct(&f,
+		validation.Field(&f.Name, validation.Length(3, 20)),
+		validation.Field(&f.Email, validation.Required),
+	)
+
+	err := errors.FromOzzoValidation(verr, "invalid form")
+	got := err.LocalizedValidationMap("fr")
+	if got["Email"] != "Email est obligatoire" {
+		t.Errorf("Expected french required message, got %q", got["Email"])
+	}
+	if got["Name"] != "Name must be between 3 and 20 characters" {
+		t.Errorf("Expected english fallback with params, got %q", got["Name"])
+	}
+
+	response := errors.LocalizedResponse(err, "fr")
+	for _, fieldErr := range response.Error.ValidationErrors {
+		if fieldErr.Message != got[fieldErr.Field] {
+			t.Errorf("Expected %q for %s, got %q", got[fieldErr.Field], fieldErr.Field, fieldErr.Message)
+		}
+	}
+
+	if err.ValidationMap()["Email"] == got["Email"] {
+		t.Error("Expected ValidationMap to keep the original messages")
+	}
+
+	multi := errors.WrapAll(errors.CategoryValidation, "request failed", err)
+	if got := multi.LocalizedValidationMap("fr")["causes.0.Email"]; got != "Email est obligatoire" {
+		t.Errorf("Expected localized multi error message, got %q", got)
+	}
+}
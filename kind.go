@@ -0,0 +1,176 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kind is a reusable error definition. It holds the category, HTTP
+// code, text code, default severity, retry behavior and message format
+// shared by every error created from it.
+//
+//	var KindUserNotFound = errors.NewKind(errors.CategoryNotFound, "USER_NOT_FOUND").
+//		WithCode(404).
+//		WithMessage("user %s not found")
+//
+//	err := KindUserNotFound.New(id)
+type Kind struct {
+	Category   Category
+	Code       int
+	TextCode   string
+	Severity   Severity
+	Retryable  bool
+	RetryDelay time.Duration
+	// Message is a fmt format applied to the args given to New and Wrap
+	Message string
+	// PublicMessage is copied to the errors created from the kind
+	PublicMessage string
+}
+
+// NewKind creates a Kind with the default severity and retry flag of
+// its category
+func NewKind(category Category, textCode string) *Kind {
+	def := DefaultCategoryRegistry.Definition(category)
+	return &Kind{
+		Category:   category,
+		TextCode:   textCode,
+		Severity:   def.Severity,
+		Retryable:  def.Retryable,
+		RetryDelay: 1 * time.Second,
+	}
+}
+
+// WithCode sets the HTTP code of errors created from the kind
+func (k *Kind) WithCode(code int) *Kind {
+	k.Code = code
+	return k
+}
+
+// WithSeverity sets the default severity of errors created from the kind
+func (k *Kind) WithSeverity(s Severity) *Kind {
+	k.Severity = s
+	return k
+}
+
+// WithMessage sets the message format of errors created from the kind
+func (k *Kind) WithMessage(format string) *Kind {
+	k.Message = format
+	return k
+}
+
+// WithPublicMessage sets the client facing message of errors created from the kind
+func (k *Kind) WithPublicMessage(message string) *Kind {
+	k.PublicMessage = message
+	return k
+}
+
+// WithRetryable sets whether errors created from the kind are retryable
+func (k *Kind) WithRetryable(retryable bool) *Kind {
+	k.Retryable = retryable
+	return k
+}
+
+// WithRetryDelay sets the base retry delay used by NewRetryable and WrapRetryable
+func (k *Kind) WithRetryDelay(delay time.Duration) *Kind {
+	k.RetryDelay = delay
+	return k
+}
+
+// New creates an Error from the kind. The args are applied to the
+// message format, when the kind has no format they are joined as
+// with fmt.Sprint
+func (k *Kind) New(args ...any) *Error {
+	return k.build(nil, args, captureLocation(1))
+}
+
+// Wrap creates an Error from the kind that wraps source.
+// Returns nil if source is nil
+func (k *Kind) Wrap(source error, args ...any) *Error {
+	if source == nil {
+		return nil
+	}
+	return k.build(source, args, captureLocation(1))
+}
+
+// NewRetryable creates a RetryableError from the kind using its retry settings
+func (k *Kind) NewRetryable(args ...any) *RetryableError {
+	return &RetryableError{
+		BaseError: k.build(nil, args, captureLocation(1)),
+		retryable: k.Retryable,
+		baseDelay: k.RetryDelay,
+	}
+}
+
+// WrapRetryable creates a RetryableError from the kind that wraps source.
+// Returns nil if source is nil
+func (k *Kind) WrapRetryable(source error, args ...any) *RetryableError {
+	if source == nil {
+		return nil
+	}
+	return &RetryableError{
+		BaseError: k.build(source, args, captureLocation(1)),
+		retryable: k.Retryable,
+		baseDelay: k.RetryDelay,
+	}
+}
+
+// Is reports whether any error in the tree of err was created from the
+// kind. Errors that lost their kind, e.g. after a JSON round-trip, match
+// on TextCode when the kind defines one
+func (k *Kind) Is(err error) bool {
+	if k == nil || err == nil {
+		return false
+	}
+
+	return Find(err, func(node error) bool {
+		e := richErrorOf(node)
+		return e != nil && (e.kind == k || (k.TextCode != "" && e.TextCode == k.TextCode))
+	}) != nil
+}
+
+// String returns the category and text code of the kind
+func (k *Kind) String() string {
+	if k.TextCode != "" {
+		return fmt.Sprintf("%s:%s", k.Category, k.TextCode)
+	}
+	return k.Category.String()
+}
+
+func (k *Kind) message(args []any) string {
+	switch {
+	case k.Message == "":
+		return fmt.Sprint(args...)
+	case len(args) == 0:
+		return k.Message
+	default:
+		return fmt.Sprintf(k.Message, args...)
+	}
+}
+
+func (k *Kind) build(source error, args []any, location *ErrorLocation) *Error {
+	return &Error{
+		Category:      k.Category,
+		Code:          k.Code,
+		TextCode:      k.TextCode,
+		Message:       k.message(args),
+		PublicMessage: k.PublicMessage,
+		Source:        source,
+		Timestamp:     time.Now(),
+		Location:      location,
+		Severity:      k.Severity,
+		kind:          k,
+	}
+}
+
+// KindOf returns the Kind the first error in the tree of err was created
+// from, or nil if none was created from a Kind
+func KindOf(err error) *Kind {
+	var kind *Kind
+	Find(err, func(node error) bool {
+		if e := richErrorOf(node); e != nil {
+			kind = e.kind
+		}
+		return kind != nil
+	})
+	return kind
+}
@@ -0,0 +1,152 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-errors"
+)
+
+var kindUserNotFound = errors.NewKind(errors.CategoryNotFound, "USER_NOT_FOUND").
+	WithCode(404).
+	WithSeverity(errors.SeverityWarning).
+	WithMessage("user %s not found")
+
+func TestKind_New(t *testing.T) {
+	err := kindUserNotFound.New("alice")
+
+	if err.Message != "user alice not found" {
+		t.Errorf("Expected formatted message, got %q", err.Message)
+	}
+	if err.Category != errors.CategoryNotFound || err.Code != 404 || err.TextCode != "USER_NOT_FOUND" {
+		t.Errorf("Expected kind fields to be applied, got %+v", err)
+	}
+	if err.Severity != errors.SeverityWarning {
+		t.Errorf("Expected severity WARNING, got %s", err.Severity)
+	}
+	if err.Location == nil || !strings.HasSuffix(err.Location.File, "kind_test.go") {
+		t.Errorf("Expected location to point at the caller, got %v", err.Location)
+	}
+	if err.Timestamp.IsZero() {
+		t.Error("Expected Timestamp to be set")
+	}
+
+	plain := errors.NewKind(errors.CategoryConflict, "DUPLICATE")
+	if got := plain.New("already", " exists").Message; got != "already exists" {
+		t.Errorf("Expected args to be joined without a format, got %q", got)
+	}
+	if got := plain.New().Severity; got != errors.SeverityError {
+		t.Errorf("Expected default severity ERROR, got %s", got)
+	}
+}
+
+func TestKind_Wrap(t *testing.T) {
+	source := fmt.Errorf("sql: no rows")
+	err := kindUserNotFound.Wrap(source, "bob")
+
+	if err.Source != source {
+		t.Errorf("Expected source to be preserved, got %v", err.Source)
+	}
+	if err.Message != "user bob not found" {
+		t.Errorf("Expected formatted message, got %q", err.Message)
+	}
+	if kindUserNotFound.Wrap(nil) != nil {
+		t.Error("Expected Wrap(nil) to return nil")
+	}
+}
+
+func TestKind_IsAndKindOf(t *testing.T) {
+	err := kindUserNotFound.New("alice")
+
+	wrapped := fmt.Errorf("handler: %w",
+		errors.Wrap(errors.Wrap(err, errors.CategoryInternal, "service"), errors.CategoryInternal, "repo"))
+
+	if !kindUserNotFound.Is(wrapped) {
+		t.Error("Expected Kind.Is to match after wrapping")
+	}
+	if errors.KindOf(wrapped) != kindUserNotFound {
+		t.Error("Expected KindOf to return the kind after wrapping")
+	}
+
+	multi := errors.WrapAll(errors.CategoryOperation, "batch", fmt.Errorf("other"), err)
+	if errors.KindOf(multi) != kindUserNotFound {
+		t.Error("Expected KindOf to search every branch")
+	}
+
+	other := errors.NewKind(errors.CategoryNotFound, "ORDER_NOT_FOUND")
+	if other.Is(wrapped) {
+		t.Error("Expected a different kind not to match")
+	}
+	if errors.KindOf(fmt.Errorf("plain")) != nil {
+		t.Error("Expected KindOf to return nil for foreign errors")
+	}
+
+	data, _ := json.Marshal(err)
+	var decoded errors.Error
+	if jsonErr := json.Unmarshal(data, &decoded); jsonErr != nil {
+		t.Fatalf("Failed to unmarshal: %v", jsonErr)
+	}
+	if !kindUserNotFound.Is(&decoded) {
+		t.Error("Expected Kind.Is to fall back to TextCode after a JSON round-trip")
+	}
+}
+
+func TestKind_Retryable(t *testing.T) {
+	kind := errors.NewKind(errors.CategoryExternal, "UPSTREAM_DOWN").
+		WithRetryable(true).
+		WithRetryDelay(250 * time.Millisecond)
+
+	retryable := kind.NewRetryable()
+	if !retryable.IsRetryable() || retryable.RetryDelay(1) != 250*time.Millisecond {
+		t.Errorf("Expected kind retry settings, got retryable=%t delay=%s", retryable.IsRetryable(), retryable.RetryDelay(1))
+	}
+	if errors.KindOf(retryable) != kind {
+		t.Error("Expected KindOf to see through RetryableError")
+	}
+
+	if !errors.IsRetryableError(kind.New()) {
+		t.Error("Expected errors from a retryable kind to be retryable")
+	}
+	if errors.IsRetryableError(kindUserNotFound.New("x")) {
+		t.Error("Expected errors from a non retryable kind not to be retryable")
+	}
+	if errors.IsRetryableError(kind.New().WithSeverity(errors.SeverityFatal)) {
+		t.Error("Expected fatal errors from a retryable kind not to be retryable")
+	}
+}
+
+func TestAuthKinds_MatchCatalog(t *testing.T) {
+	kinds := []*errors.Kind{
+		errors.KindTooManyAttempts, errors.KindTokenExpired, errors.KindInviteExpired,
+		errors.KindUnauthorized, errors.KindForbidden, errors.KindFeatureDisabled,
+	}
+	for _, kind := range kinds {
+		def, ok := errors.LookupCode(kind.TextCode)
+		if !ok {
+			t.Errorf("Expected %s to be registered", kind.TextCode)
+			continue
+		}
+		if def.Category != kind.Category || def.Status != kind.Code {
+			t.Errorf("%s: expected %s/%d from the catalog, got %s/%d",
+				kind.TextCode, def.Category, def.Status, kind.Category, kind.Code)
+		}
+	}
+}
+
+func TestMappers_UseKinds(t *testing.T) {
+	err := errors.MapAuthErrors(fmt.Errorf("token expired"))
+	if errors.KindOf(err) != errors.KindTokenExpired {
+		t.Errorf("Expected MapAuthErrors result to carry KindTokenExpired, got %v", errors.KindOf(err))
+	}
+	if err.Message != "token expired" {
+		t.Errorf("Expected original message, got %q", err.Message)
+	}
+
+	err = errors.MapOnboardingErrors(fmt.Errorf("invite expired"))
+	if !errors.KindInviteExpired.Is(err) {
+		t.Error("Expected MapOnboardingErrors result to carry KindInviteExpired")
+	}
+}
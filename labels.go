@@ -0,0 +1,359 @@
+package errors
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// FieldLabels maps validation field paths such as address.zip_code to
+// human labels such as "ZIP code". Lookups fall back to the parent
+// registry, DefaultFieldLabels for registries built with StructLabels
+type FieldLabels struct {
+	mu     sync.RWMutex
+	labels map[string]string
+	parent *FieldLabels
+}
+
+// DefaultFieldLabels is the global label registry
+var DefaultFieldLabels = NewFieldLabels()
+
+// NewFieldLabels creates an empty registry without parent
+func NewFieldLabels() *FieldLabels {
+	return &FieldLabels{labels: make(map[string]string)}
+}
+
+// StructLabels creates a registry from the `label` struct tags of v,
+// keyed by the json field names ozzo uses (the Go name without a json
+// tag). Nested structs are registered under dotted paths. Labels not
+// found fall back to DefaultFieldLabels
+//
+//	type Address struct {
+//		ZipCode string `json:"zip_code" label:"ZIP code"`
+//	}
+func StructLabels(v any) *FieldLabels {
+	labels := NewFieldLabels()
+	labels.parent = DefaultFieldLabels
+	labels.SetStruct(v)
+	return labels
+}
+
+// Set registers label for a field path or a bare field name, a bare
+// name matches the last segment of any path. An empty label removes it
+func (l *FieldLabels) Set(field, label string) *FieldLabels {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if label == "" {
+		delete(l.labels, field)
+		return l
+	}
+	l.labels[field] = label
+	return l
+}
+
+// SetMap registers every field to label entry
+func (l *FieldLabels) SetMap(labels map[string]string) *FieldLabels {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for field, label := range labels {
+		l.labels[field] = label
+	}
+	return l
+}
+
+// SetStruct registers the `label` struct tags of v, see StructLabels
+func (l *FieldLabels) SetStruct(v any) *FieldLabels {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return l
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.setStruct("", t, make(map[reflect.Type]bool))
+	return l
+}
+
+func (l *FieldLabels) setStruct(prefix string, t reflect.Type, visited map[reflect.Type]bool) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		path := prefixKey(prefix, name)
+
+		if label := field.Tag.Get("label"); label != "" {
+			l.labels[path] = label
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			l.setStruct(path, ft, visited)
+		}
+	}
+}
+
+// Label returns the label of a field path in locale. The localizer keys
+// field.<path> are tried first, then the registered labels, for the
+// path, the path without its leading segments (source.email, email)
+// and without list indexes (items.0.name, items.name). Unknown fields
+// are humanized from their last segment, zip_code becomes "Zip code"
+func (l *FieldLabels) Label(field, locale string) string {
+	candidates := labelCandidates(field)
+
+	if localizer != nil {
+		for _, candidate := range candidates {
+			if label, ok := localizer.Localize(locale, "field."+candidate, nil); ok {
+				return label
+			}
+		}
+	}
+
+	for registry := l; registry != nil; registry = registry.parent {
+		if label, ok := registry.lookup(candidates); ok {
+			return label
+		}
+	}
+
+	return HumanizeFieldName(field)
+}
+
+func (l *FieldLabels) lookup(candidates []string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, candidate := range candidates {
+		if label, ok := l.labels[candidate]; ok {
+			return label, true
+		}
+	}
+	return "", false
+}
+
+// labelCandidates lists the keys tried for a field path, most specific first
+func labelCandidates(field string) []string {
+	var segments []string
+	for _, segment := range strings.Split(field, ".") {
+		if segment != "" && strings.Trim(segment, "0123456789") != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	candidates := []string{field}
+	for i := range segments {
+		candidate := strings.Join(segments[i:], ".")
+		if candidate != candidates[len(candidates)-1] {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// HumanizeFieldName turns the last segment of a field path into a
+// label: zip_code, zipCode and zip-code become "Zip code"
+func HumanizeFieldName(field string) string {
+	segments := labelCandidates(field)
+	name := segments[len(segments)-1]
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	if len(words) == 0 {
+		return field
+	}
+
+	for i, w := range words {
+		// keep acronyms such as ID or URL
+		if strings.ToUpper(w) != w || len(w) == 1 {
+			words[i] = strings.ToLower(w)
+		}
+	}
+	first := []rune(words[0])
+	first[0] = unicode.ToUpper(first[0])
+	words[0] = string(first)
+
+	return strings.Join(words, " ")
+}
+
+// fieldMessageTemplates are the message templates keyed by rule code
+var fieldMessageTemplates = map[string]string{
+	"validation_required":                        "{label} is required",
+	"validation_nil_or_not_empty_required":       "{label} is required",
+	"validation_not_nil_required":                "{label} is required",
+	"validation_nil":                             "{label} must be blank",
+	"validation_empty":                           "{label} must be blank",
+	"validation_length_too_long":                 "{label} must be at most {max} characters long",
+	"validation_length_too_short":                "{label} must be at least {min} characters long",
+	"validation_length_invalid":                  "{label} must be exactly {min} characters long",
+	"validation_length_out_of_range":             "{label} must be between {min} and {max} characters long",
+	"validation_length_empty_required":           "{label} must be empty",
+	"validation_date_out_of_range":               "{label} is out of range",
+	"validation_min_greater_equal_than_required": "{label} must be at least {threshold}",
+	"validation_max_less_equal_than_required":    "{label} must be at most {threshold}",
+	"validation_key_missing":                     "{label} is missing",
+	"validation_key_unexpected":                  "{label} is not expected",
+	"validation_key_wrong_type":                  "{label} has the wrong type",
+}
+
+var fieldMessageTemplatesMu sync.RWMutex
+
+// SetFieldMessageTemplate sets the message template of a validation rule
+// code. Templates use {label}, {field}, {value} and the rule params,
+// e.g. "{label} must be at least {min} characters long". An empty
+// template removes it. Localized messages for the code take precedence
+func SetFieldMessageTemplate(code, template string) {
+	fieldMessageTemplatesMu.Lock()
+	defer fieldMessageTemplatesMu.Unlock()
+	if template == "" {
+		delete(fieldMessageTemplates, code)
+		return
+	}
+	fieldMessageTemplates[code] = template
+}
+
+// FieldMessageTemplate returns the message template of a rule code
+func FieldMessageTemplate(code string) (string, bool) {
+	fieldMessageTemplatesMu.RLock()
+	defer fieldMessageTemplatesMu.RUnlock()
+	template, ok := fieldMessageTemplates[code]
+	return template, ok
+}
+
+// verbPrefixes start messages that read as a sentence after the label,
+// as ozzo messages do ("must be a valid email address")
+var verbPrefixes = []string{
+	"is ", "are ", "must ", "cannot ", "can't ", "can ", "should ", "has ", "have ",
+	"does ", "do ", "was ", "were ", "may ", "needs ", "contains ",
+}
+
+// HumanizeFieldError rewrites the message of fieldErr into human phrasing
+// with the field label and sets Label, Field keeps the machine path for
+// clients that bind errors to inputs. The message is, in order, the
+// localized message of the rule code, its template, or the original
+// message prefixed with the label. labels defaults to DefaultFieldLabels
+func HumanizeFieldError(fieldErr FieldError, labels *FieldLabels, locale string) FieldError {
+	if labels == nil {
+		labels = DefaultFieldLabels
+	}
+	if fieldErr.Label == "" {
+		fieldErr.Label = labels.Label(fieldErr.Field, locale)
+	}
+
+	if fieldErr.Code != "" {
+		params := fieldErrorParams(fieldErr, locale)
+		if message, ok := localizeField(fieldErr, locale, params); ok {
+			fieldErr.Message = message
+			return fieldErr
+		}
+		if template, ok := FieldMessageTemplate(fieldErr.Code); ok {
+			fieldErr.Message = interpolate(template, params)
+			return fieldErr
+		}
+	}
+
+	fieldErr.Message = humanizeMessage(fieldErr.Label, fieldErr.Field, fieldErr.Message)
+	return fieldErr
+}
+
+func humanizeMessage(label, field, message string) string {
+	message = strings.TrimSpace(message)
+	lower := strings.ToLower(message)
+
+	switch {
+	case message == "":
+		return label + " is invalid"
+	case lower == "required":
+		return label + " is required"
+	case strings.HasPrefix(lower, strings.ToLower(label)+" "), field != "" && strings.HasPrefix(lower, strings.ToLower(field)+" "):
+		return capitalize(message)
+	}
+
+	for _, prefix := range verbPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return label + " " + message
+		}
+	}
+	return label + ": " + message
+}
+
+func capitalize(s string) string {
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// HumanizeValidation rewrites the messages of the error validation errors,
+// see HumanizeFieldError. labels defaults to DefaultFieldLabels
+func (e *Error) HumanizeValidation(labels *FieldLabels, locale string) *Error {
+	if e == nil || len(e.ValidationErrors) == 0 {
+		return e
+	}
+	e = e.mutable()
+	humanized := make(ValidationErrors, len(e.ValidationErrors))
+	for i, fieldErr := range e.ValidationErrors {
+		humanized[i] = HumanizeFieldError(fieldErr, labels, locale)
+	}
+	e.ValidationErrors = humanized
+	return e
+}
+
+// HumanizedValidationMap is ValidationMap with humanized messages
+func (e *Error) HumanizedValidationMap(labels *FieldLabels, locale string) map[string]string {
+	return humanizedFieldMessages(e.validationFieldsWithPath(""), labels, locale)
+}
+
+// HumanizedValidationMap is ValidationMap with humanized messages
+func (m *MultiError) HumanizedValidationMap(labels *FieldLabels, locale string) map[string]string {
+	return humanizedFieldMessages(m.validationFields(), labels, locale)
+}
+
+func humanizedFieldMessages(fields map[string]FieldError, labels *FieldLabels, locale string) map[string]string {
+	result := make(map[string]string, len(fields))
+	for k, fieldErr := range fields {
+		result[k] = HumanizeFieldError(fieldErr, labels, locale).Message
+	}
+	return result
+}
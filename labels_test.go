@@ -0,0 +1,185 @@
+package errors_test
+
+import (
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/goliatone/go-errors"
+)
+
+type labelAddress struct {
+	ZipCode string `json:"zip_code" label:"ZIP code"`
+	City    string `json:"city"`
+}
+
+type labelForm struct {
+	Email   string        `json:"email" label:"Email address"`
+	Name    string        `json:"name"`
+	Address *labelAddress `json:"address"`
+	Tags    []labelAddress
+	Secret  string `json:"-" label:"Hidden"`
+}
+
+func TestHumanizeFieldName(t *testing.T) {
+	tests := map[string]string{
+		"zip_code":         "Zip code",
+		"address.zip_code": "Zip code",
+		"zipCode":          "Zip code",
+		"first-name":       "First name",
+		"UserID":           "User ID",
+		"HTTPServer":       "HTTP server",
+		"items.0":          "Items",
+		"email":            "Email",
+	}
+	for input, want := range tests {
+		if got := errors.HumanizeFieldName(input); got != want {
+			t.Errorf("Expected %q for %s, got %q", want, input, got)
+		}
+	}
+}
+
+func TestStructLabels(t *testing.T) {
+	labels := errors.StructLabels(&labelForm{})
+
+	tests := map[string]string{
+		"email":                 "Email address",
+		"address.zip_code":      "ZIP code",
+		"source.email":          "Email address",
+		"causes.0.address.city": "City",
+		"Tags.0.zip_code":       "ZIP code",
+		"name":                  "Name",
+	}
+	for field, want := range tests {
+		if got := labels.Label(field, "en"); got != want {
+			t.Errorf("Expected %q for %s, got %q", want, field, got)
+		}
+	}
+
+	if got := labels.Label("Secret", "en"); got != "Secret" {
+		t.Errorf("Expected json:\"-\" field to be skipped, got %q", got)
+	}
+}
+
+func TestFieldLabels_GlobalFallback(t *testing.T) {
+	errors.DefaultFieldLabels.Set("dob", "Date of birth")
+	t.Cleanup(func() { errors.DefaultFieldLabels.Set("dob", "") })
+
+	labels := errors.StructLabels(labelForm{})
+	if got := labels.Label("profile.dob", "en"); got != "Date of birth" {
+		t.Errorf("Expected global label, got %q", got)
+	}
+}
+
+func TestHumanizeFieldError(t *testing.T) {
+	labels := errors.NewFieldLabels().Set("zip_code", "ZIP code")
+
+	tests := []struct {
+		name     string
+		fieldErr errors.FieldError
+		want     string
+	}{
+		{
+			name:     "template",
+			fieldErr: errors.FieldError{Field: "address.zip_code", Message: "cannot be blank", Code: "validation_required"},
+			want:     "ZIP code is required",
+		},
+		{
+			name: "template params",
+			fieldErr: errors.FieldError{
+				Field: "name", Message: "the length must be between 3 and 20",
+				Code: "validation_length_out_of_range", Params: map[string]any{"min": 3, "max": 20},
+			},
+			want: "Name must be between 3 and 20 characters long",
+		},
+		{
+			name:     "ozzo message without template",
+			fieldErr: errors.FieldError{Field: "email", Message: "must be a valid email address", Code: "validation_is_email"},
+			want:     "Email must be a valid email address",
+		},
+		{
+			name:     "native verb message",
+			fieldErr: errors.FieldError{Field: "age", Message: "must be positive"},
+			want:     "Age must be positive",
+		},
+		{
+			name:     "native required",
+			fieldErr: errors.FieldError{Field: "zip_code", Message: "required"},
+			want:     "ZIP code is required",
+		},
+		{
+			name:     "message naming the field",
+			fieldErr: errors.FieldError{Field: "email", Message: "email is taken"},
+			want:     "Email is taken",
+		},
+		{
+			name:     "other message",
+			fieldErr: errors.FieldError{Field: "email", Message: "invalid format"},
+			want:     "Email: invalid format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errors.HumanizeFieldError(tt.fieldErr, labels, "en")
+			if got.Message != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got.Message)
+			}
+			if got.Field != tt.fieldErr.Field {
+				t.Errorf("Expected field path %s to be kept, got %s", tt.fieldErr.Field, got.Field)
+			}
+		})
+	}
+}
+
+func TestHumanizeValidation_Ozzo(t *testing.T) {
+	form := labelForm{Email: "nope", Address: &labelAddress{}}
+	verr := validation.ValidateStruct(&form,
+		validation.Field(&form.Email, is.Email),
+		validation.Field(&form.Address),
+	)
+	addrErr := validation.ValidateStruct(form.Address, validation.Field(&form.Address.ZipCode, validation.Required))
+	verr.(validation.Errors)["address"] = addrErr
+
+	err := errors.FromOzzoValidation(verr, "invalid form")
+
+	got := err.HumanizedValidationMap(errors.StructLabels(form), "en")
+	if got["address.zip_code"] != "ZIP code is required" {
+		t.Errorf("Expected humanized nested message, got %q", got["address.zip_code"])
+	}
+	if got["email"] != "Email address must be a valid email address" {
+		t.Errorf("Expected humanized email message, got %q", got["email"])
+	}
+
+	humanized := err.HumanizeValidation(errors.StructLabels(form), "en")
+	for _, fieldErr := range humanized.ValidationErrors {
+		if fieldErr.Label == "" {
+			t.Errorf("Expected label for %s", fieldErr.Field)
+		}
+	}
+}
+
+func TestHumanizeFieldError_Localized(t *testing.T) {
+	catalog := errors.NewMessageCatalog("en")
+	if err := catalog.LoadJSON("fr", []byte(`{
+		"field": {"zip_code": "Code postal"},
+		"validation_required": "{label} est obligatoire"
+	}`)); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	withLocalizer(t, catalog)
+
+	fieldErr := errors.FieldError{Field: "address.zip_code", Message: "cannot be blank", Code: "validation_required"}
+
+	got := errors.HumanizeFieldError(fieldErr, nil, "fr")
+	if got.Message != "Code postal est obligatoire" {
+		t.Errorf("Expected localized message, got %q", got.Message)
+	}
+	if got.Label != "Code postal" {
+		t.Errorf("Expected localized label, got %q", got.Label)
+	}
+
+	if msg := errors.LocalizeFieldError(fieldErr, "fr"); msg != "Code postal est obligatoire" {
+		t.Errorf("Expected label param in localized message, got %q", msg)
+	}
+}
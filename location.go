@@ -59,3 +59,11 @@ func captureLocation(skip int) *ErrorLocation {
 func Here() *ErrorLocation {
 	return captureLocation(1) // Skip Here() itself
 }
+
+// CallerLocation captures the location skip frames above the function
+// calling it. CallerLocation(0) is equivalent to Here(), CallerLocation(1)
+// returns the location of the caller of that function. Useful for
+// constructor helpers that should report where they were called from
+func CallerLocation(skip int) *ErrorLocation {
+	return captureLocation(skip + 1)
+}
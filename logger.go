@@ -7,6 +7,10 @@ import (
 func ToSlogAttributes(err error) []slog.Attr {
 	var richErr *Error
 	if As(err, &richErr) {
+		// fingerprint the original error, redaction may change the message
+		fingerprint := richErr.Fingerprint()
+		richErr = redactionPolicy.Log.Redact(richErr)
+
 		var attrs []slog.Attr
 		if richErr.Code != 0 {
 			attrs = append(attrs, slog.Int("error_code", richErr.Code))
@@ -20,9 +24,15 @@ func ToSlogAttributes(err error) []slog.Attr {
 			attrs = append(attrs, slog.String("category", richErr.Category.String()))
 		}
 
+		attrs = append(attrs, slog.String("fingerprint", fingerprint))
+
 		// Add severity information
 		attrs = append(attrs, slog.String("severity", richErr.Severity.String()))
 
+		if richErr.PublicMessage != "" {
+			attrs = append(attrs, slog.String("public_message", richErr.PublicMessage))
+		}
+
 		if richErr.RequestID != "" {
 			attrs = append(attrs, slog.String("request_id", richErr.RequestID))
 		}
@@ -46,6 +56,7 @@ func LogBySeverity(logger *slog.Logger, err *Error) {
 	}
 
 	attrs := ToSlogAttributes(err)
+	err = redactionPolicy.Log.Redact(err)
 	// Convert []slog.Attr to []any for logging methods
 	anyAttrs := make([]any, len(attrs))
 	for i, attr := range attrs {
@@ -0,0 +1,340 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// MultiError extends Error with several causes. It implements
+// Unwrap() []error so errors.Is and errors.As search every branch,
+// while errors.As with an *Error target resolves to the BaseError.
+type MultiError struct {
+	*BaseError
+	Causes []error
+}
+
+func (m *MultiError) Error() string {
+	base := "multi error: <nil>"
+	if m.BaseError != nil {
+		base = m.BaseError.Error()
+	}
+
+	if len(m.Causes) == 0 {
+		return base
+	}
+
+	parts := make([]string, len(m.Causes))
+	for i, cause := range m.Causes {
+		parts[i] = cause.Error()
+	}
+	return fmt.Sprintf("%s; causes: [%s]", base, strings.Join(parts, "; "))
+}
+
+// Unwrap returns all causes of the error
+func (m *MultiError) Unwrap() []error {
+	if m == nil {
+		return nil
+	}
+	return m.Causes
+}
+
+// As resolves an *Error target to the BaseError
+func (m *MultiError) As(target any) bool {
+	if t, ok := target.(**Error); ok && m.BaseError != nil {
+		*t = m.BaseError
+		return true
+	}
+	return false
+}
+
+// AllValidationErrors returns the validation errors of the BaseError
+// and of every cause
+func (m *MultiError) AllValidationErrors() ValidationErrors {
+	var allErrors ValidationErrors
+	if m.BaseError != nil {
+		allErrors = append(allErrors, m.BaseError.AllValidationErrors()...)
+	}
+	for _, cause := range m.Causes {
+		allErrors = append(allErrors, collectValidationErrors(cause)...)
+	}
+	return allErrors
+}
+
+// ToErrorResponse returns the response of the BaseError carrying the
+// validation errors of every cause
+func (m *MultiError) ToErrorResponse(includeStack bool, stackTrace StackTrace) ErrorResponse {
+	if m == nil || m.BaseError == nil {
+		return ErrorResponse{}
+	}
+	base := m.BaseError.Clone()
+	base.ValidationErrors = m.AllValidationErrors()
+	return base.ToErrorResponse(includeStack, stackTrace)
+}
+
+// ValidationMap returns validation errors as a map, cause entries are
+// prefixed with causes.<index>
+func (m *MultiError) ValidationMap() map[string]string {
+	return fieldMessages(m.validationFields())
+}
+
+// validationFields returns the field errors of the base error and of
+// every cause keyed by their path
+func (m *MultiError) validationFields() map[string]FieldError {
+	result := make(map[string]FieldError)
+	if m.BaseError != nil {
+		for k, v := range m.BaseError.validationFieldsWithPath("") {
+			result[k] = v
+		}
+	}
+	for i, cause := range m.Causes {
+		for k, v := range validationFieldsOf(cause, "causes."+strconv.Itoa(i)) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Clone creates a copy of the error and its list of causes
+func (m *MultiError) Clone() *MultiError {
+	if m == nil {
+		return nil
+	}
+	return &MultiError{
+		BaseError: m.BaseError.Clone(),
+		Causes:    append([]error(nil), m.Causes...),
+	}
+}
+
+func (m *MultiError) WithMetadata(metas ...map[string]any) *MultiError {
+	m = m.mutable()
+	if m.BaseError != nil {
+		m.BaseError.WithMetadata(metas...)
+	}
+	return m
+}
+
+func (m *MultiError) WithStackTrace() *MultiError {
+	m = m.mutable()
+	if m.BaseError != nil {
+		m.BaseError.StackTrace = CaptureStackTrace(1)
+	}
+	return m
+}
+
+func (m *MultiError) WithCode(code int) *MultiError {
+	m = m.mutable()
+	if m.BaseError != nil {
+		m.BaseError.WithCode(code)
+	}
+	return m
+}
+
+func (m *MultiError) WithTextCode(code string) *MultiError {
+	m = m.mutable()
+	if m.BaseError != nil {
+		m.BaseError.WithTextCode(code)
+	}
+	return m
+}
+
+// WithPublicMessage sets the message shown to API clients
+func (m *MultiError) WithPublicMessage(message string) *MultiError {
+	m = m.mutable()
+	if m.BaseError != nil {
+		m.BaseError.PublicMessage = message
+	}
+	return m
+}
+
+// WithLocation sets the location where the error occurred
+func (m *MultiError) WithLocation(loc *ErrorLocation) *MultiError {
+	m = m.mutable()
+	if m.BaseError != nil {
+		m.BaseError.WithLocation(loc)
+	}
+	return m
+}
+
+// WithSeverity sets the severity level of the error
+func (m *MultiError) WithSeverity(s Severity) *MultiError {
+	m = m.mutable()
+	if m.BaseError != nil {
+		m.BaseError.WithSeverity(s)
+	}
+	return m
+}
+
+// MarshalJSON implements JSON marshaling for MultiError, every cause
+// is listed in the causes array
+func (m *MultiError) MarshalJSON() ([]byte, error) {
+	if redactionPolicy.JSON != nil {
+		m = redactionPolicy.JSON.RedactError(m).(*MultiError)
+	}
+	return m.marshalJSON()
+}
+
+func (m *MultiError) marshalJSON() ([]byte, error) {
+	base := m.BaseError
+	if base == nil {
+		base = &Error{}
+	}
+
+	aux := base.toJSON()
+	causes, err := encodeCauses(m.Causes)
+	if err != nil {
+		return nil, err
+	}
+	aux.Causes = causes
+
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON implements JSON unmarshaling for MultiError
+func (m *MultiError) UnmarshalJSON(data []byte) error {
+	var aux errorJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	base := &Error{}
+	causes, err := base.fromJSON(aux)
+	if err != nil {
+		return err
+	}
+
+	m.BaseError = base
+	m.Causes = causes
+	return nil
+}
+
+// Format implements fmt.Formatter for MultiError, the detailed form
+// lists every cause
+func (m *MultiError) Format(s fmt.State, verb rune) {
+	if m == nil {
+		io.WriteString(s, "<nil>")
+		return
+	}
+
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('#'):
+			fmt.Fprintf(s, "&errors.MultiError{BaseError:%#v, Causes:%#v}", m.BaseError, m.Causes)
+		case s.Flag('+'):
+			m = redactionPolicy.Log.RedactError(m).(*MultiError)
+			if m.BaseError != nil {
+				io.WriteString(s, m.BaseError.detailedString())
+			} else {
+				io.WriteString(s, "multi error: <nil>")
+			}
+			for i, cause := range m.Causes {
+				fmt.Fprintf(s, "\ncaused by [%d]: %+v", i, cause)
+			}
+		default:
+			io.WriteString(s, m.Error())
+		}
+	case 's':
+		io.WriteString(s, m.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", m.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(*errors.MultiError=%s)", verb, m.Error())
+	}
+}
+
+// WrapAll creates a new MultiError that wraps all non nil errs.
+// Returns nil if there is nothing to wrap
+func WrapAll(category Category, message string, errs ...error) *MultiError {
+	var causes []error
+	for _, err := range errs {
+		if err != nil {
+			causes = append(causes, err)
+		}
+	}
+
+	if len(causes) == 0 {
+		return nil
+	}
+
+	return &MultiError{
+		BaseError: &Error{
+			Category:  category,
+			Message:   message,
+			Timestamp: time.Now(),
+			Location:  captureLocation(1),
+			Severity:  categorySeverity(category),
+		},
+		Causes: causes,
+	}
+}
+
+// validationFieldsOf returns the field errors of the tree of err keyed
+// by their path, see ValidationMap
+func validationFieldsOf(err error, prefix string) map[string]FieldError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *Error:
+		return e.validationFieldsWithPath(prefix)
+	case *MultiError:
+		result := make(map[string]FieldError)
+		for k, v := range e.validationFields() {
+			result[prefixKey(prefix, k)] = v
+		}
+		return result
+	case *RetryableError:
+		if e.BaseError != nil {
+			return e.BaseError.validationFieldsWithPath(prefix)
+		}
+		return nil
+	case validation.Errors:
+		result := make(map[string]FieldError)
+		for _, fieldErr := range fromOzzoFieldErrors(e) {
+			result[prefixKey(prefix, fieldErr.Field)] = fieldErr
+		}
+		return result
+	}
+
+	children := unwrapAll(err)
+	if len(children) == 1 {
+		return validationFieldsOf(children[0], prefix)
+	}
+
+	result := make(map[string]FieldError)
+	for i, child := range children {
+		for k, v := range validationFieldsOf(child, prefixKey(prefix, strconv.Itoa(i))) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// fieldMessages maps every path to its field error message
+func fieldMessages(fields map[string]FieldError) map[string]string {
+	result := make(map[string]string, len(fields))
+	for k, fieldErr := range fields {
+		result[k] = fieldErr.Message
+	}
+	return result
+}
+
+func prefixKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func fromOzzoFieldErrors(validationErrors validation.Errors) ValidationErrors {
+	var fieldErrors ValidationErrors
+	for field, fieldErr := range validationErrors {
+		fieldErrors = append(fieldErrors, fieldErrorFromOzzo(field, fieldErr))
+	}
+	return fieldErrors
+}
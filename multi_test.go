@@ -0,0 +1,171 @@
+package errors_test
+
+import (
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/goliatone/go-errors"
+)
+
+func TestWrapAll(t *testing.T) {
+	notFound := errors.New("user missing", errors.CategoryNotFound)
+	foreign := fmt.Errorf("disk full")
+
+	err := errors.WrapAll(errors.CategoryOperation, "batch failed", notFound, nil, foreign)
+	if err == nil {
+		t.Fatal("Expected WrapAll to return an error")
+	}
+
+	if len(err.Causes) != 2 {
+		t.Fatalf("Expected nil causes to be dropped, got %d causes", len(err.Causes))
+	}
+	if err.Category != errors.CategoryOperation {
+		t.Errorf("Expected category operation, got %s", err.Category)
+	}
+	if err.Location == nil || !strings.HasSuffix(err.Location.File, "multi_test.go") {
+		t.Errorf("Expected location to point at the caller, got %v", err.Location)
+	}
+
+	unwrapped := err.Unwrap()
+	if len(unwrapped) != 2 || unwrapped[0] != notFound || unwrapped[1] != foreign {
+		t.Errorf("Unexpected Unwrap() result: %v", unwrapped)
+	}
+
+	if !stdErrors.Is(err, notFound) || !stdErrors.Is(err, foreign) {
+		t.Error("Expected errors.Is to find every cause")
+	}
+
+	var rich *errors.Error
+	if !errors.As(err, &rich) || rich != err.BaseError {
+		t.Error("Expected errors.As to resolve *Error to the base error")
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, "batch failed") || !strings.Contains(msg, "user missing") || !strings.Contains(msg, "disk full") {
+		t.Errorf("Expected message to list all causes, got %q", msg)
+	}
+
+	if errors.WrapAll(errors.CategoryInternal, "nothing", nil, nil) != nil {
+		t.Error("Expected WrapAll with only nil errors to return nil")
+	}
+}
+
+func TestMultiCause_ChainHelpers(t *testing.T) {
+	validationErr := errors.NewValidation("invalid",
+		errors.FieldError{Field: "email", Message: "required"})
+	authErr := errors.New("expired", errors.CategoryAuth)
+
+	joined := errors.Join(fmt.Errorf("first"), fmt.Errorf("ctx: %w", validationErr), authErr)
+
+	if !errors.HasCategory(joined, errors.CategoryValidation) {
+		t.Error("Expected HasCategory to find validation in a joined branch")
+	}
+	if !errors.HasCategory(joined, errors.CategoryAuth) {
+		t.Error("Expected HasCategory to find auth in a joined branch")
+	}
+	if errors.HasCategory(joined, errors.CategoryConflict) {
+		t.Error("Expected HasCategory to return false for a missing category")
+	}
+
+	fieldErrs, ok := errors.GetValidationErrors(joined)
+	if !ok || len(fieldErrs) != 1 || fieldErrs[0].Field != "email" {
+		t.Errorf("Expected validation errors from joined branch, got %v (found=%t)", fieldErrs, ok)
+	}
+
+	wrapped := errors.Wrap(joined, errors.CategoryInternal, "request failed")
+	all := wrapped.AllValidationErrors()
+	if len(all) != 1 || all[0].Field != "email" {
+		t.Errorf("Expected AllValidationErrors to traverse joined source, got %v", all)
+	}
+
+	multi := errors.WrapAll(errors.CategoryOperation, "batch failed",
+		authErr,
+		validationErr,
+		validation.Errors{"name": validation.ErrRequired},
+	)
+
+	if !errors.HasCategory(multi, errors.CategoryValidation) {
+		t.Error("Expected HasCategory to find validation in a MultiError cause")
+	}
+	if !errors.IsCategory(multi, errors.CategoryOperation) {
+		t.Error("Expected IsCategory to match the MultiError category")
+	}
+
+	all = multi.AllValidationErrors()
+	if len(all) != 2 {
+		t.Errorf("Expected 2 validation errors across causes, got %v", all)
+	}
+
+	validationMap := multi.ValidationMap()
+	if validationMap["causes.1.email"] != "required" {
+		t.Errorf("Expected nested key causes.1.email, got %v", validationMap)
+	}
+	if _, ok := validationMap["causes.2.name"]; !ok {
+		t.Errorf("Expected nested key causes.2.name, got %v", validationMap)
+	}
+
+	if root := errors.RootCause(multi); root != authErr {
+		t.Errorf("Expected RootCause to follow the first branch, got %v", root)
+	}
+	if cat := errors.RootCategory(multi); cat != errors.CategoryAuth {
+		t.Errorf("Expected RootCategory auth, got %s", cat)
+	}
+}
+
+func TestMultiError_JSONRoundTrip(t *testing.T) {
+	multi := errors.WrapAll(errors.CategoryOperation, "batch failed",
+		errors.New("user missing", errors.CategoryNotFound).WithTextCode("USER_NOT_FOUND"),
+		fmt.Errorf("disk full"),
+	)
+
+	data, err := json.Marshal(multi)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	var decoded errors.MultiError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+
+	if decoded.Message != "batch failed" || decoded.Category != errors.CategoryOperation {
+		t.Errorf("Unexpected base error: %+v", decoded.BaseError)
+	}
+	if len(decoded.Causes) != 2 {
+		t.Fatalf("Expected 2 causes, got %d", len(decoded.Causes))
+	}
+
+	first, ok := decoded.Causes[0].(*errors.Error)
+	if !ok || first.TextCode != "USER_NOT_FOUND" {
+		t.Errorf("Expected structured first cause, got %#v", decoded.Causes[0])
+	}
+	if decoded.Causes[1].Error() != "disk full" {
+		t.Errorf("Expected foreign cause text, got %q", decoded.Causes[1].Error())
+	}
+
+	var plain errors.Error
+	if err := json.Unmarshal(data, &plain); err != nil {
+		t.Fatalf("Failed to unmarshal into Error: %v", err)
+	}
+	if !errors.HasCategory(&plain, errors.CategoryNotFound) {
+		t.Error("Expected causes to be reachable when decoding into *Error")
+	}
+}
+
+func TestMultiError_Format(t *testing.T) {
+	multi := errors.WrapAll(errors.CategoryOperation, "batch failed",
+		errors.New("user missing", errors.CategoryNotFound),
+		fmt.Errorf("disk full"),
+	)
+
+	detailed := fmt.Sprintf("%+v", multi)
+	for _, want := range []string{"[operation] batch failed", "caused by [0]: [not_found] user missing", "caused by [1]: disk full"} {
+		if !strings.Contains(detailed, want) {
+			t.Errorf("Expected %%+v output to contain %q, got:\n%s", want, detailed)
+		}
+	}
+}
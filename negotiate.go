@@ -0,0 +1,166 @@
+package errors
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LanguagePreference is a language range from an Accept-Language header
+type LanguagePreference struct {
+	Tag     string
+	Quality float64
+}
+
+// ParseAcceptLanguage parses an Accept-Language header into preferences
+// sorted by quality, highest first. Ranges with q=0 or an invalid q are
+// dropped, equal qualities keep the header order
+func ParseAcceptLanguage(header string) []LanguagePreference {
+	var prefs []LanguagePreference
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		valid := true
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			quality = q
+		}
+		if !valid || quality == 0 {
+			continue
+		}
+
+		prefs = append(prefs, LanguagePreference{Tag: tag, Quality: quality})
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].Quality > prefs[j].Quality
+	})
+	return prefs
+}
+
+// NegotiateLocale picks the locale of available that best matches the
+// Accept-Language header. Each preference is tried in order: an exact
+// match, then its parents (pt-BR, then pt), then a regional variant of
+// its language (pt matches pt-BR). defaultLocale is returned when
+// nothing matches. The result is in canonical form, e.g. pt-BR
+func NegotiateLocale(acceptLanguage string, available []string, defaultLocale string) string {
+	normalized := make(map[string]string, len(available))
+	for _, locale := range available {
+		normalized[normalizeLocale(locale)] = locale
+	}
+
+	for _, pref := range ParseAcceptLanguage(acceptLanguage) {
+		tag := normalizeLocale(pref.Tag)
+		if tag == "*" {
+			break
+		}
+
+		for candidate := tag; ; {
+			if _, ok := normalized[candidate]; ok {
+				return CanonicalLocale(candidate)
+			}
+			i := strings.LastIndex(candidate, "-")
+			if i < 0 {
+				break
+			}
+			candidate = candidate[:i]
+		}
+
+		language, _, _ := strings.Cut(tag, "-")
+		var variants []string
+		for candidate := range normalized {
+			if strings.HasPrefix(candidate, language+"-") {
+				variants = append(variants, candidate)
+			}
+		}
+		if len(variants) > 0 {
+			sort.Strings(variants)
+			return CanonicalLocale(variants[0])
+		}
+	}
+
+	return CanonicalLocale(defaultLocale)
+}
+
+// CanonicalLocale formats a locale tag the way it is usually written:
+// lowercase language, titlecase script and uppercase region (zh-Hant-TW)
+func CanonicalLocale(locale string) string {
+	parts := strings.Split(normalizeLocale(locale), "-")
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			parts[i] = strings.ToUpper(parts[i])
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// localeSource is implemented by localizers that can list their locales,
+// such as MessageCatalog
+type localeSource interface {
+	Locales() []string
+	DefaultLocale() string
+}
+
+// RequestLocale negotiates the locale of r against the locales of the
+// configured Localizer. It is empty when no localizer is set, r is nil or
+// the localizer cannot list the locales it supports
+func RequestLocale(r *http.Request) string {
+	locale, supported := requestLocale(r)
+	if !supported {
+		return ""
+	}
+	return locale
+}
+
+// requestLocale returns the locale to localize r with and whether the
+// localizer is known to support it. Localizers that cannot list their
+// locales get the first preferred language, unconfirmed
+func requestLocale(r *http.Request) (string, bool) {
+	if r == nil || localizer == nil {
+		return "", false
+	}
+
+	header := r.Header.Get("Accept-Language")
+	if source, ok := localizer.(localeSource); ok {
+		return NegotiateLocale(header, source.Locales(), source.DefaultLocale()), true
+	}
+
+	for _, pref := range ParseAcceptLanguage(header) {
+		if pref.Tag != "*" {
+			return CanonicalLocale(pref.Tag), false
+		}
+	}
+	return "", false
+}
+
+// NegotiatedResponse is LocalizedResponse with the locale negotiated from
+// the Accept-Language header of r. It adds Accept-Language to Vary and
+// sets Content-Language when the localizer supports the locale or the
+// message was translated
+func NegotiatedResponse(w http.ResponseWriter, r *http.Request, err error) ErrorResponse {
+	locale, supported := requestLocale(r)
+	w.Header().Add("Vary", "Accept-Language")
+
+	response, translated := localizedResponse(err, locale)
+	if locale != "" && (supported || translated) {
+		w.Header().Set("Content-Language", locale)
+	}
+	return response
+}
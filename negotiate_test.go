@@ -0,0 +1,155 @@
+package errors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-errors"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	prefs := errors.ParseAcceptLanguage("fr-CH, fr;q=0.9, en;q=0.8, de;q=0, it;q=bad, *;q=0.5")
+
+	want := []errors.LanguagePreference{
+		{Tag: "fr-CH", Quality: 1},
+		{Tag: "fr", Quality: 0.9},
+		{Tag: "en", Quality: 0.8},
+		{Tag: "*", Quality: 0.5},
+	}
+	if len(prefs) != len(want) {
+		t.Fatalf("Expected %d preferences, got %d", len(want), len(prefs))
+	}
+	for i := range want {
+		if prefs[i] != want[i] {
+			t.Errorf("Expected %+v at %d, got %+v", want[i], i, prefs[i])
+		}
+	}
+
+	if prefs := errors.ParseAcceptLanguage(""); len(prefs) != 0 {
+		t.Errorf("Expected no preferences, got %d", len(prefs))
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	available := []string{"en", "fr", "pt", "es-MX"}
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"exact", "fr", "fr"},
+		{"parent", "pt-BR", "pt"},
+		{"quality order", "de;q=0.9, fr;q=0.3, pt;q=0.5", "pt"},
+		{"regional variant", "es", "es-MX"},
+		{"skip unavailable", "de, fr;q=0.8", "fr"},
+		{"wildcard", "de, *;q=0.1", "en"},
+		{"no match", "de", "en"},
+		{"empty", "", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.NegotiateLocale(tt.header, available, "en"); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCanonicalLocale(t *testing.T) {
+	tests := map[string]string{
+		"pt-br":      "pt-BR",
+		"EN_us":      "en-US",
+		"zh-hant-tw": "zh-Hant-TW",
+		"fr":         "fr",
+	}
+	for input, want := range tests {
+		if got := errors.CanonicalLocale(input); got != want {
+			t.Errorf("Expected %s for %s, got %s", want, input, got)
+		}
+	}
+}
+
+func TestNegotiatedResponse(t *testing.T) {
+	withLocalizer(t, newTestCatalog(t))
+
+	err := errors.New("user lookup failed", errors.CategoryNotFound).
+		WithCode(404).
+		WithTextCode("USER_NOT_FOUND").
+		WithMetadata(map[string]any{"user_id": 42})
+
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	r.Header.Set("Accept-Language", "pt-BR;q=0.8, fr-CA")
+	w := httptest.NewRecorder()
+
+	response := errors.NegotiatedResponse(w, r, err)
+	if response.Error.Message != "Utilisateur 42 introuvable" {
+		t.Errorf("Expected french message, got %q", response.Error.Message)
+	}
+	if got := w.Header().Get("Content-Language"); got != "fr" {
+		t.Errorf("Expected Content-Language fr, got %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Language" {
+		t.Errorf("Expected Vary Accept-Language, got %q", got)
+	}
+
+	r.Header.Set("Accept-Language", "pt-BR")
+	w = httptest.NewRecorder()
+	response = errors.NegotiatedResponse(w, r, err)
+	if got := w.Header().Get("Content-Language"); got != "pt-BR" {
+		t.Errorf("Expected Content-Language pt-BR, got %q", got)
+	}
+	if response.Error.Message != "Usuário 42 não encontrado" {
+		t.Errorf("Expected portuguese message, got %q", response.Error.Message)
+	}
+
+	r.Header.Set("Accept-Language", "ja")
+	w = httptest.NewRecorder()
+	errors.NegotiatedResponse(w, r, err)
+	if got := w.Header().Get("Content-Language"); got != "en" {
+		t.Errorf("Expected default Content-Language en, got %q", got)
+	}
+}
+
+// germanOnly translates every key in de and nothing else, it cannot
+// list its locales
+type germanOnly struct{}
+
+func (germanOnly) Localize(locale, key string, _ map[string]any) (string, bool) {
+	if locale != "de" {
+		return "", false
+	}
+	return "Nicht gefunden", true
+}
+
+func TestNegotiatedResponse_UnsupportedLocale(t *testing.T) {
+	err := errors.New("user lookup failed", errors.CategoryNotFound)
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	r.Header.Set("Accept-Language", "fr")
+
+	withLocalizer(t, nil)
+	w := httptest.NewRecorder()
+	errors.NegotiatedResponse(w, r, err)
+	if got := w.Header().Get("Content-Language"); got != "" {
+		t.Errorf("Expected no Content-Language without a localizer, got %q", got)
+	}
+	if got := errors.RequestLocale(r); got != "" {
+		t.Errorf("Expected no locale without a localizer, got %q", got)
+	}
+
+	errors.SetLocalizer(germanOnly{})
+	w = httptest.NewRecorder()
+	errors.NegotiatedResponse(w, r, err)
+	if got := w.Header().Get("Content-Language"); got != "" {
+		t.Errorf("Expected no Content-Language for an untranslated body, got %q", got)
+	}
+
+	r.Header.Set("Accept-Language", "de")
+	w = httptest.NewRecorder()
+	response := errors.NegotiatedResponse(w, r, err)
+	if got := w.Header().Get("Content-Language"); got != "de" || response.Error.Message != "Nicht gefunden" {
+		t.Errorf("Expected a german response, got %q %q", got, response.Error.Message)
+	}
+}
@@ -1,47 +1,27 @@
 package errors
 
-import "net/http"
-
 // MapOnboardingErrors normalizes invite, reset, verification, and feature gate errors.
 func MapOnboardingErrors(err error) *Error {
 	msg := normalizeErrorMessage(err)
 	switch {
 	case containsAny(msg, "invite expired", "invitation expired") || containsAll(msg, "invite", "expired"):
-		return New(err.Error(), CategoryBadInput).
-			WithCode(http.StatusGone).
-			WithTextCode(TextCodeInviteExpired)
+		return KindInviteExpired.New(err.Error())
 	case containsAny(msg, "invite used", "invitation used", "invite already used") || containsAll(msg, "invite", "used"):
-		return New(err.Error(), CategoryConflict).
-			WithCode(http.StatusConflict).
-			WithTextCode(TextCodeInviteUsed)
+		return KindInviteUsed.New(err.Error())
 	case containsAny(msg, "token already used"):
-		return New(err.Error(), CategoryConflict).
-			WithCode(http.StatusConflict).
-			WithTextCode(TextCodeTokenAlreadyUsed)
+		return KindTokenAlreadyUsed.New(err.Error())
 	case containsAny(msg, "reset not allowed", "password reset not allowed"):
-		return New(err.Error(), CategoryAuthz).
-			WithCode(http.StatusForbidden).
-			WithTextCode(TextCodeResetNotAllowed)
+		return KindResetNotAllowed.New(err.Error())
 	case containsAny(msg, "reset rate limit", "password reset rate limit", "password reset rate limited", "password reset is rate limited"):
-		return New(err.Error(), CategoryRateLimit).
-			WithCode(http.StatusTooManyRequests).
-			WithTextCode(TextCodeResetRateLimit)
+		return KindResetRateLimit.New(err.Error())
 	case containsAny(msg, "account locked", "account lockout", "locked out"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusForbidden).
-			WithTextCode(TextCodeAccountLocked)
+		return KindAccountLocked.New(err.Error())
 	case containsAny(msg, "verification required", "verification needed", "email not verified", "email verification required"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusForbidden).
-			WithTextCode(TextCodeVerificationRequired)
+		return KindVerificationRequired.New(err.Error())
 	case containsAny(msg, "verification expired", "verification token expired"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusForbidden).
-			WithTextCode(TextCodeVerificationExpired)
+		return KindVerificationExpired.New(err.Error())
 	case containsAny(msg, "feature disabled", "signup disabled", "registration disabled", "self registration disabled"):
-		return New(err.Error(), CategoryAuthz).
-			WithCode(http.StatusForbidden).
-			WithTextCode(TextCodeFeatureDisabled)
+		return KindFeatureDisabled.New(err.Error())
 	}
 	return nil
 }
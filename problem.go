@@ -0,0 +1,349 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBlank is the problem type used when the error has no
+// documented text code, the title is then the HTTP status text
+const ProblemTypeBlank = "about:blank"
+
+// Problem is an RFC 9457 problem details document. Extensions holds
+// every member other than the standard ones, it is flattened into the
+// document when encoded
+type Problem struct {
+	Type       string         `json:"type,omitempty"`
+	Title      string         `json:"title,omitempty"`
+	Status     int            `json:"status,omitempty"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+// Extension members written by ToProblem and read by FromProblem
+const (
+	ProblemTextCode  = "text_code"
+	ProblemCategory  = "category"
+	ProblemErrors    = "errors"
+	ProblemRequestID = "request_id"
+	ProblemTimestamp = "timestamp"
+)
+
+var problemStandardMembers = map[string]bool{
+	"type": true, "title": true, "status": true, "detail": true, "instance": true,
+}
+
+var problemTypeBaseURL string
+
+// SetProblemTypeBaseURL sets the base of the problem type of text codes
+// without a DocsURL in the DefaultCatalog, e.g. with
+// "https://example.com/problems/" USER_NOT_FOUND gets
+// https://example.com/problems/user-not-found. Empty (the default)
+// uses about:blank. Meant to be called during startup
+func SetProblemTypeBaseURL(base string) {
+	problemTypeBaseURL = base
+}
+
+// ProblemType returns the problem type URI of a text code: its DocsURL
+// in the DefaultCatalog, else a URI under the base set with
+// SetProblemTypeBaseURL, else about:blank
+func ProblemType(textCode string) string {
+	if textCode == "" {
+		return ProblemTypeBlank
+	}
+	if def, ok := LookupCode(textCode); ok && def.DocsURL != "" {
+		return def.DocsURL
+	}
+	if problemTypeBaseURL != "" {
+		return problemTypeBaseURL + strings.ReplaceAll(strings.ToLower(textCode), "_", "-")
+	}
+	return ProblemTypeBlank
+}
+
+// MarshalJSON flattens Extensions next to the standard members, which
+// take precedence
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		if !problemStandardMembers[k] {
+			members[k] = v
+		}
+	}
+
+	if p.Type != "" {
+		members["type"] = p.Type
+	}
+	if p.Title != "" {
+		members["title"] = p.Title
+	}
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON reads the standard members and collects the others in
+// Extensions. Standard members with the wrong type are ignored, as
+// RFC 9457 requires
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	*p = Problem{}
+	for name, raw := range members {
+		var target any
+		switch name {
+		case "type":
+			target = &p.Type
+		case "title":
+			target = &p.Title
+		case "status":
+			target = &p.Status
+		case "detail":
+			target = &p.Detail
+		case "instance":
+			target = &p.Instance
+		default:
+			var value any
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return err
+			}
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]any)
+			}
+			p.Extensions[name] = value
+			continue
+		}
+		_ = json.Unmarshal(raw, target)
+	}
+
+	if p.Type == "" {
+		p.Type = ProblemTypeBlank
+	}
+	return nil
+}
+
+// ToProblem converts the error to problem details shaped for clients,
+// the client redactor and the response policy apply as in
+// ToErrorResponse. The detail is the message clients see, validation
+// errors are listed in the errors member and metadata keys become
+// extension members. instance identifies the occurrence, usually the
+// request path, and may be empty
+func (e *Error) ToProblem(instance string) *Problem {
+	status := e.HTTPStatus()
+	response := e.ToErrorResponse(false, nil).Error
+	return problemFromResponse(response, status, instance)
+}
+
+// problemFromResponse builds the problem from a response error, the
+// stack trace and location are only added when the response policy of
+// the current Mode exposes them
+func problemFromResponse(e *Error, status int, instance string) *Problem {
+	rules := GetResponsePolicy(CurrentMode()).rulesFor(e)
+	problem := &Problem{
+		Type:       ProblemType(e.TextCode),
+		Status:     status,
+		Detail:     e.Message,
+		Instance:   instance,
+		Extensions: make(map[string]any),
+	}
+
+	problem.Title = http.StatusText(status)
+	if problem.Type != ProblemTypeBlank {
+		if def, ok := LookupCode(e.TextCode); ok && def.Description != "" {
+			problem.Title = def.Description
+		}
+	}
+
+	for k, v := range e.Metadata {
+		problem.Extensions[k] = v
+	}
+
+	if e.TextCode != "" {
+		problem.Extensions[ProblemTextCode] = e.TextCode
+	}
+	if e.Category != "" {
+		problem.Extensions[ProblemCategory] = e.Category.String()
+	}
+	if e.RequestID != "" {
+		problem.Extensions[ProblemRequestID] = e.RequestID
+	}
+	if !e.Timestamp.IsZero() {
+		problem.Extensions[ProblemTimestamp] = e.Timestamp.Format(time.RFC3339Nano)
+	}
+	if len(e.ValidationErrors) > 0 {
+		problem.Extensions[ProblemErrors] = e.ValidationErrors
+	}
+	if e.PublicMessage != "" {
+		problem.Extensions["public_message"] = e.PublicMessage
+	}
+	if rules.ExposeStackTrace && len(e.StackTrace) > 0 {
+		problem.Extensions["stack_trace"] = e.StackTrace
+	}
+	if rules.ExposeLocation && e.Location != nil {
+		problem.Extensions["location"] = e.Location
+	}
+
+	return problem
+}
+
+// ToError converts problem details, typically from an upstream service,
+// into an Error. The category comes from the category member or from
+// the status with HTTPStatusToCategory. The errors member becomes the
+// validation errors and the other extension members the metadata, the
+// type and instance are kept as problem_type and problem_instance
+func (p *Problem) ToError() *Error {
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	message := p.Detail
+	if message == "" {
+		message = p.Title
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	category := HTTPStatusToCategory(status)
+	textCode := HTTPStatusToTextCode(status)
+	e := &Error{
+		Category:  category,
+		Code:      status,
+		TextCode:  textCode,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+
+	metadata := make(map[string]any)
+	for name, value := range p.Extensions {
+		switch name {
+		case ProblemCategory:
+			if s, ok := value.(string); ok && s != "" {
+				e.Category = Category(s)
+				continue
+			}
+		case ProblemTextCode:
+			if s, ok := value.(string); ok && s != "" {
+				e.TextCode = s
+				continue
+			}
+		case ProblemRequestID:
+			if s, ok := value.(string); ok {
+				e.RequestID = s
+				continue
+			}
+		case ProblemTimestamp:
+			if s, ok := value.(string); ok {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					e.Timestamp = t
+					continue
+				}
+			}
+		case ProblemErrors:
+			if fieldErrors, ok := problemFieldErrors(value); ok {
+				e.ValidationErrors = fieldErrors
+				continue
+			}
+		case "public_message":
+			if s, ok := value.(string); ok {
+				e.PublicMessage = s
+				continue
+			}
+		}
+		metadata[name] = value
+	}
+
+	e.Severity = categorySeverity(e.Category)
+
+	if p.Type != "" && p.Type != ProblemTypeBlank {
+		metadata["problem_type"] = p.Type
+	}
+	if p.Instance != "" {
+		metadata["problem_instance"] = p.Instance
+	}
+	if len(metadata) > 0 {
+		e.Metadata = metadata
+	}
+
+	return e
+}
+
+// problemFieldErrors reads an errors member, items may use field, name
+// or pointer for the field and message, detail or reason for the message
+func problemFieldErrors(value any) (ValidationErrors, bool) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+
+	fieldErrors := make(ValidationErrors, 0, len(items))
+	for _, item := range items {
+		member, ok := item.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		fieldErr := FieldError{
+			Field:   firstString(member, "field", "name", "pointer"),
+			Message: firstString(member, "message", "detail", "reason"),
+			Value:   member["value"],
+			Code:    firstString(member, "code"),
+			Label:   firstString(member, "label"),
+		}
+		fieldErr.Field = strings.ReplaceAll(strings.TrimPrefix(fieldErr.Field, "#/"), "/", ".")
+		if params, ok := member["params"].(map[string]any); ok {
+			fieldErr.Params = params
+		}
+		fieldErrors = append(fieldErrors, fieldErr)
+	}
+	return fieldErrors, true
+}
+
+func firstString(member map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := member[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// FromProblem converts problem details into an Error, see Problem.ToError
+func FromProblem(p *Problem) *Error {
+	if p == nil {
+		return nil
+	}
+	return p.ToError()
+}
+
+// ParseProblem decodes an application/problem+json document into an Error
+func ParseProblem(data []byte) (*Error, error) {
+	var problem Problem
+	if err := json.Unmarshal(data, &problem); err != nil {
+		return nil, fmt.Errorf("parse problem details: %w", err)
+	}
+	return problem.ToError(), nil
+}
+
+// WriteProblem maps err and writes it as application/problem+json, the
+// instance is the request path
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error, opts ...HTTPOption) {
+	newHTTPOptions(opts).writeWith(w, r, err, ProblemFormatter{})
+}
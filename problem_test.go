@@ -0,0 +1,219 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-errors"
+)
+
+func TestError_ToProblem(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	err := errors.NewValidation("signup payload invalid",
+		errors.FieldError{Field: "email", Message: "must be a valid email address", Code: "validation_is_email"},
+	).WithTextCode("SIGNUP_INVALID").
+		WithRequestID("req-1").
+		WithMetadata(map[string]any{"plan": "pro"})
+
+	problem := err.ToProblem("/signup")
+
+	if problem.Type != errors.ProblemTypeBlank {
+		t.Errorf("Expected about:blank type, got %s", problem.Type)
+	}
+	if problem.Status != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", problem.Status)
+	}
+	if problem.Title != "Bad Request" {
+		t.Errorf("Expected status text title, got %q", problem.Title)
+	}
+	if problem.Detail != errors.CategoryPublicMessage(errors.CategoryValidation) {
+		t.Errorf("Expected public detail, got %q", problem.Detail)
+	}
+	if problem.Instance != "/signup" {
+		t.Errorf("Expected instance /signup, got %s", problem.Instance)
+	}
+
+	data, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		t.Fatalf("Failed to marshal: %v", marshalErr)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	for _, member := range []string{"type", "title", "status", "detail", "instance", "text_code", "category", "request_id", "errors", "plan"} {
+		if _, ok := doc[member]; !ok {
+			t.Errorf("Expected member %s in %s", member, data)
+		}
+	}
+	for _, member := range []string{"location", "stack_trace"} {
+		if _, ok := doc[member]; ok {
+			t.Errorf("Expected no member %s in production, got %s", member, data)
+		}
+	}
+}
+
+func TestError_ToProblemDevelopment(t *testing.T) {
+	withMode(t, errors.ModeDevelopment)
+
+	problem := errors.New("boom", errors.CategoryConflict).ToProblem("")
+	if _, ok := problem.Extensions["location"]; !ok {
+		t.Error("Expected the location member in development")
+	}
+}
+
+func TestProblemType(t *testing.T) {
+	// registered once per process, RegisterCode fails on reruns
+	_ = errors.RegisterCode(errors.CodeDefinition{
+		TextCode:    "PROBLEM_TEST_QUOTA",
+		Category:    errors.CategoryRateLimit,
+		Status:      http.StatusTooManyRequests,
+		Description: "Quota exceeded",
+		DocsURL:     "https://docs.example.com/errors/quota",
+	})
+
+	problem := errors.New("quota", errors.CategoryRateLimit).WithTextCode("PROBLEM_TEST_QUOTA").ToProblem("")
+	if problem.Type != "https://docs.example.com/errors/quota" {
+		t.Errorf("Expected docs URL type, got %s", problem.Type)
+	}
+	if problem.Title != "Quota exceeded" {
+		t.Errorf("Expected catalog title, got %q", problem.Title)
+	}
+
+	errors.SetProblemTypeBaseURL("https://example.com/problems/")
+	t.Cleanup(func() { errors.SetProblemTypeBaseURL("") })
+
+	if got := errors.ProblemType("USER_NOT_FOUND"); got != "https://example.com/problems/user-not-found" {
+		t.Errorf("Expected base URL type, got %s", got)
+	}
+	if got := errors.ProblemType(""); got != errors.ProblemTypeBlank {
+		t.Errorf("Expected about:blank without text code, got %s", got)
+	}
+}
+
+func TestParseProblem(t *testing.T) {
+	doc := `{
+		"type": "https://upstream.example.com/probs/out-of-credit",
+		"title": "You do not have enough credit.",
+		"status": 403,
+		"detail": "Your current balance is 30, but that costs 50.",
+		"instance": "/account/12345/msgs/abc",
+		"balance": 30,
+		"errors": [
+			{"pointer": "#/amount", "detail": "must be positive"},
+			{"name": "currency", "reason": "unsupported"}
+		]
+	}`
+
+	e, err := errors.ParseProblem([]byte(doc))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if e.Category != errors.CategoryAuthz {
+		t.Errorf("Expected category from status, got %s", e.Category)
+	}
+	if e.Code != http.StatusForbidden || e.TextCode != "FORBIDDEN" {
+		t.Errorf("Expected 403/FORBIDDEN, got %d/%s", e.Code, e.TextCode)
+	}
+	if e.Message != "Your current balance is 30, but that costs 50." {
+		t.Errorf("Expected detail as message, got %q", e.Message)
+	}
+	if e.Metadata["balance"] != float64(30) {
+		t.Errorf("Expected balance extension in metadata, got %v", e.Metadata["balance"])
+	}
+	if e.Metadata["problem_type"] != "https://upstream.example.com/probs/out-of-credit" {
+		t.Errorf("Expected problem type in metadata, got %v", e.Metadata["problem_type"])
+	}
+	if e.Metadata["problem_instance"] != "/account/12345/msgs/abc" {
+		t.Errorf("Expected problem instance in metadata, got %v", e.Metadata["problem_instance"])
+	}
+
+	fields := e.ValidationMap()
+	if fields["amount"] != "must be positive" || fields["currency"] != "unsupported" {
+		t.Errorf("Expected validation errors from errors member, got %v", fields)
+	}
+
+	if e.Severity != errors.SeverityError {
+		t.Errorf("Expected the category severity, got %s", e.Severity)
+	}
+
+	if _, err := errors.ParseProblem([]byte("not json")); err == nil {
+		t.Error("Expected error for invalid document")
+	}
+}
+
+func TestProblem_ToErrorCategorySeverity(t *testing.T) {
+	category := errors.Category("problem_severity_test")
+	errors.MustRegisterCategory(errors.NewCategoryDefinition(category).WithSeverity(errors.SeverityWarning))
+	t.Cleanup(func() { errors.DefaultCategoryRegistry.Unregister(category) })
+
+	problem := &errors.Problem{
+		Status:     http.StatusConflict,
+		Extensions: map[string]any{errors.ProblemCategory: category.String()},
+	}
+	if got := problem.ToError().Severity; got != errors.SeverityWarning {
+		t.Errorf("Expected severity of the resolved category, got %s", got)
+	}
+}
+
+func TestProblem_RoundTrip(t *testing.T) {
+	withMode(t, errors.ModeDevelopment)
+
+	original := errors.New("order 7 conflicts", errors.CategoryConflict).
+		WithTextCode("ORDER_CONFLICT").
+		WithRequestID("req-7").
+		WithMetadata(map[string]any{"order_id": "7"})
+
+	data, err := json.Marshal(original.ToProblem("/orders/7"))
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	parsed, err := errors.ParseProblem(data)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if parsed.Category != original.Category || parsed.TextCode != original.TextCode {
+		t.Errorf("Expected %s/%s, got %s/%s", original.Category, original.TextCode, parsed.Category, parsed.TextCode)
+	}
+	if parsed.Code != http.StatusConflict || parsed.RequestID != "req-7" {
+		t.Errorf("Expected 409 and request ID, got %d %q", parsed.Code, parsed.RequestID)
+	}
+	if parsed.Message != "order 7 conflicts" {
+		t.Errorf("Expected message, got %q", parsed.Message)
+	}
+	if parsed.Metadata["order_id"] != "7" {
+		t.Errorf("Expected metadata, got %v", parsed.Metadata)
+	}
+	if !parsed.Timestamp.Equal(original.Timestamp) {
+		t.Errorf("Expected timestamp %s, got %s", original.Timestamp, parsed.Timestamp)
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users/9", nil)
+	w := httptest.NewRecorder()
+
+	errors.WriteProblem(w, r, errors.New("missing", errors.CategoryNotFound))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != errors.ProblemContentType {
+		t.Errorf("Expected problem content type, got %q", got)
+	}
+
+	var problem errors.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if problem.Status != http.StatusNotFound || problem.Instance != "/users/9" {
+		t.Errorf("Expected status and instance, got %d %q", problem.Status, problem.Instance)
+	}
+}
@@ -0,0 +1,326 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// DefaultRedactionMask replaces redacted metadata and field values
+const DefaultRedactionMask = "[REDACTED]"
+
+// Scrubber replaces sensitive values found in text
+type Scrubber struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Replacement string
+	// Validate, if set, filters matches, e.g. a Luhn check for card numbers
+	Validate func(match string) bool
+}
+
+// Scrub replaces every match of the scrubber in text
+func (s Scrubber) Scrub(text string) string {
+	if s.Pattern == nil {
+		return text
+	}
+	if s.Validate == nil {
+		return s.Pattern.ReplaceAllString(text, s.Replacement)
+	}
+	return s.Pattern.ReplaceAllStringFunc(text, func(match string) string {
+		if s.Validate(match) {
+			return s.Replacement
+		}
+		return match
+	})
+}
+
+// Built in scrubbers
+var (
+	ScrubJWT = Scrubber{
+		Name:        "jwt",
+		Pattern:     regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		Replacement: "[REDACTED_JWT]",
+	}
+	ScrubBearerToken = Scrubber{
+		Name:        "bearer",
+		Pattern:     regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		Replacement: "Bearer [REDACTED]",
+	}
+	ScrubCardNumber = Scrubber{
+		Name:        "card",
+		Pattern:     regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Replacement: "[REDACTED_CARD]",
+		Validate:    luhnValid,
+	}
+	ScrubEmail = Scrubber{
+		Name:        "email",
+		Pattern:     regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		Replacement: "[REDACTED_EMAIL]",
+	}
+	// ScrubSQL is not part of the defaults since it can match plain sentences
+	ScrubSQL = Scrubber{
+		Name:        "sql",
+		Pattern:     regexp.MustCompile(`(?i)\b(?:select|insert|update|delete)\b[^;]*?\b(?:from|into|set|where)\b[^;]*`),
+		Replacement: "[REDACTED_SQL]",
+	}
+)
+
+// DefaultScrubbers returns scrubbers for JWTs, bearer tokens, card numbers and emails
+func DefaultScrubbers() []Scrubber {
+	// bearer runs first so the token is not left as a partial JWT match
+	return []Scrubber{ScrubBearerToken, ScrubJWT, ScrubCardNumber, ScrubEmail}
+}
+
+// DefaultDenyKeys are metadata keys redacted by DefaultRedactor
+var DefaultDenyKeys = []string{
+	"password", "passwd", "secret", "token", "apikey", "authorization",
+	"cookie", "session", "creditcard", "cardnumber", "cvv", "ssn", "privatekey",
+}
+
+// Redactor removes sensitive data from errors before they are written.
+//
+// Metadata keys are compared case insensitive ignoring "_", "-", "." and
+// spaces, so "api_key" and "APIKey" are the same key. A key is denied when
+// it contains a DenyKeys entry. When AllowKeys is set any key not listed
+// is redacted too. Scrubbers run on messages, source text, FieldError
+// messages and string values
+type Redactor struct {
+	DenyKeys  []string
+	AllowKeys []string
+	Scrubbers []Scrubber
+	// Mask replaces redacted values, defaults to DefaultRedactionMask
+	Mask string
+}
+
+// DefaultRedactor returns a Redactor using DefaultDenyKeys and DefaultScrubbers
+func DefaultRedactor() *Redactor {
+	return &Redactor{
+		DenyKeys:  append([]string(nil), DefaultDenyKeys...),
+		Scrubbers: DefaultScrubbers(),
+	}
+}
+
+// RedactionPolicy selects the Redactor used for each output, a nil
+// Redactor leaves that output untouched
+type RedactionPolicy struct {
+	// Log applies to ToSlogAttributes, LogBySeverity, collector logging
+	// and the %+v format
+	Log *Redactor
+	// JSON applies to every MarshalJSON call, including responses
+	JSON *Redactor
+	// Client applies to ToErrorResponse
+	Client *Redactor
+}
+
+var redactionPolicy RedactionPolicy
+
+// SetRedactionPolicy sets the redactors used by each output.
+// Meant to be called during startup
+func SetRedactionPolicy(policy RedactionPolicy) {
+	redactionPolicy = policy
+}
+
+// GetRedactionPolicy returns the current redaction policy
+func GetRedactionPolicy() RedactionPolicy {
+	return redactionPolicy
+}
+
+// Redact returns a redacted copy of e covering messages, metadata,
+// validation errors and the whole source tree. Returns e when r is nil
+func (r *Redactor) Redact(e *Error) *Error {
+	if r == nil || e == nil {
+		return e
+	}
+
+	redacted := e.outputClone()
+	redacted.Message = r.RedactString(e.Message)
+	redacted.PublicMessage = r.RedactString(e.PublicMessage)
+	redacted.Metadata = r.RedactMetadata(e.Metadata)
+	redacted.ValidationErrors = r.RedactFieldErrors(e.ValidationErrors)
+	redacted.Source = r.RedactError(e.Source)
+	return redacted
+}
+
+// RedactError returns a redacted copy of err. Rich errors keep their
+// structure, any other error is replaced by its scrubbed text
+func (r *Redactor) RedactError(err error) error {
+	if r == nil || err == nil {
+		return err
+	}
+
+	switch source := err.(type) {
+	case *Error:
+		return r.Redact(source)
+	case *MultiError:
+		causes := make([]error, len(source.Causes))
+		for i, cause := range source.Causes {
+			causes[i] = r.RedactError(cause)
+		}
+		return &MultiError{BaseError: r.Redact(source.BaseError), Causes: causes}
+	case *RetryableError:
+		return &RetryableError{
+			BaseError: r.Redact(source.BaseError),
+			retryable: source.retryable,
+			baseDelay: source.baseDelay,
+		}
+	case validation.Errors:
+		redacted := make(validation.Errors, len(source))
+		for field, fieldErr := range source {
+			redacted[field] = r.RedactError(fieldErr)
+		}
+		return redacted
+	case interface{ Unwrap() []error }:
+		branches := source.Unwrap()
+		redacted := make([]error, len(branches))
+		for i, branch := range branches {
+			redacted[i] = r.RedactError(branch)
+		}
+		return Join(redacted...)
+	default:
+		return &opaqueError{msg: r.RedactString(err.Error())}
+	}
+}
+
+// RedactString applies the scrubbers to s
+func (r *Redactor) RedactString(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, scrubber := range r.Scrubbers {
+		s = scrubber.Scrub(s)
+	}
+	return s
+}
+
+// RedactMetadata returns a redacted copy of metadata, nested maps and
+// slices are redacted too
+func (r *Redactor) RedactMetadata(metadata map[string]any) map[string]any {
+	if r == nil || metadata == nil {
+		return metadata
+	}
+
+	redacted := make(map[string]any, len(metadata))
+	for key, value := range metadata {
+		if r.deniedKey(key) {
+			redacted[key] = r.mask()
+			continue
+		}
+		redacted[key] = r.redactValue(value)
+	}
+	return redacted
+}
+
+// RedactFieldErrors returns a redacted copy of errs. Values of denied
+// fields are masked, messages and string values are scrubbed
+func (r *Redactor) RedactFieldErrors(errs ValidationErrors) ValidationErrors {
+	if r == nil || errs == nil {
+		return errs
+	}
+
+	redacted := make(ValidationErrors, len(errs))
+	for i, fieldErr := range errs {
+		redacted[i] = fieldErr
+		redacted[i].Message = r.RedactString(fieldErr.Message)
+		redacted[i].Value = r.redactValue(fieldErr.Value)
+		if fieldErr.Value != nil && r.matchesKey(r.DenyKeys, fieldErr.Field) {
+			redacted[i].Value = r.mask()
+		}
+	}
+	return redacted
+}
+
+func (r *Redactor) redactValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return r.RedactString(v)
+	case map[string]any:
+		return r.RedactMetadata(v)
+	case map[string]string:
+		redacted := make(map[string]any, len(v))
+		for key, value := range v {
+			redacted[key] = value
+		}
+		return r.RedactMetadata(redacted)
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = r.redactValue(item)
+		}
+		return redacted
+	case []string:
+		redacted := make([]string, len(v))
+		for i, item := range v {
+			redacted[i] = r.RedactString(item)
+		}
+		return redacted
+	case error:
+		return r.RedactString(v.Error())
+	default:
+		return value
+	}
+}
+
+func (r *Redactor) deniedKey(key string) bool {
+	if r.matchesKey(r.DenyKeys, key) {
+		return true
+	}
+	if len(r.AllowKeys) == 0 {
+		return false
+	}
+
+	normalized := normalizeRedactionKey(key)
+	for _, allowed := range r.AllowKeys {
+		if normalizeRedactionKey(allowed) == normalized {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesKey returns true if key contains any of the patterns
+func (r *Redactor) matchesKey(patterns []string, key string) bool {
+	normalized := normalizeRedactionKey(key)
+	for _, pattern := range patterns {
+		if p := normalizeRedactionKey(pattern); p != "" && strings.Contains(normalized, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) mask() string {
+	if r.Mask == "" {
+		return DefaultRedactionMask
+	}
+	return r.Mask
+}
+
+var redactionKeyReplacer = strings.NewReplacer("_", "", "-", "", ".", "", " ", "")
+
+func normalizeRedactionKey(key string) string {
+	return strings.ToLower(redactionKeyReplacer.Replace(key))
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum, count := 0, 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+		count++
+	}
+	return count >= 13 && sum%10 == 0
+}
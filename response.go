@@ -13,8 +13,15 @@ type ErrorResponse struct {
 	Error *Error `json:"error"`
 }
 
+// ToErrorResponse returns a copy of the error shaped for API clients. The
+// client redactor and the response policy of the current Mode decide
+// which details are kept. In production server errors only carry a
+// generic message and a correlation ID (the RequestID)
 func (e *Error) ToErrorResponse(includeStack bool, stackTrace StackTrace) ErrorResponse {
-	responseError := e.Clone()
+	responseError := e.outputClone()
+	if redactionPolicy.Client != nil {
+		responseError = redactionPolicy.Client.Redact(e)
+	}
 	response := ErrorResponse{
 		Error: responseError,
 	}
@@ -23,20 +30,31 @@ func (e *Error) ToErrorResponse(includeStack bool, stackTrace StackTrace) ErrorR
 		return response
 	}
 
-	if includeStack {
-		if stackTrace != nil {
-			response.Error.StackTrace = make(StackTrace, len(stackTrace))
-			copy(response.Error.StackTrace, stackTrace)
-		} else {
-			response.Error.StackTrace = nil
-		}
+	if includeStack && stackTrace != nil {
+		response.Error.StackTrace = make(StackTrace, len(stackTrace))
+		copy(response.Error.StackTrace, stackTrace)
 	} else {
 		response.Error.StackTrace = nil
 	}
 
+	if response.Error.Code == 0 {
+		response.Error.Code = e.HTTPStatus()
+	}
+
+	GetResponsePolicy(CurrentMode()).apply(response.Error, includeStack)
+
 	return response
 }
 
+// HTTPStatus returns the HTTP status of the error: its Code when it is a
+// valid HTTP error status, the status of its category otherwise
+func (e *Error) HTTPStatus() int {
+	if e.Code >= 400 && e.Code <= 599 {
+		return e.Code
+	}
+	return CategoryHTTPStatus(e.Category)
+}
+
 // MapToError converts any error to our Error type using provided mappers
 func MapToError(err error, mappers []ErrorMapper) *Error {
 	if err == nil {
@@ -58,8 +76,8 @@ func MapToError(err error, mappers []ErrorMapper) *Error {
 	}
 
 	customErr = Wrap(err, CategoryInternal, "An unexpected error occurred")
-	customErr.Code = 500
-	customErr.TextCode = "INTERNAL_ERROR"
+	customErr.Code = CategoryHTTPStatus(CategoryInternal)
+	customErr.TextCode = TextCodeInternalError
 
 	return customErr
 }
@@ -69,6 +87,7 @@ func DefaultErrorMappers() []ErrorMapper {
 		MapOnboardingErrors,
 		MapAuthErrors,
 		MapHTTPErrors,
+		MapGRPCErrors,
 	}
 }
 
@@ -79,6 +98,7 @@ func MapHTTPErrors(err error) *Error {
 		category := HTTPStatusToCategory(code)
 
 		result := New(err.Error(), category).
+			WithPublicMessage(http.StatusText(code)).
 			WithCode(code).
 			WithTextCode(HTTPStatusToTextCode(code))
 
@@ -92,67 +112,31 @@ func MapAuthErrors(err error) *Error {
 	msg := normalizeErrorMessage(err)
 	switch {
 	case containsAny(msg, "too many attempts", "too many login attempts"):
-		return New(err.Error(), CategoryRateLimit).
-			WithCode(http.StatusTooManyRequests).
-			WithTextCode(TextCodeTooManyAttempts)
+		return KindTooManyAttempts.New(err.Error())
 	case containsAny(msg, "token expired", "token is expired"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusUnauthorized).
-			WithTextCode(TextCodeTokenExpired)
+		return KindTokenExpired.New(err.Error())
 	case containsAny(msg, "token malformed", "token is malformed", "malformed token", "missing or malformed jwt"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusBadRequest).
-			WithTextCode(TextCodeTokenMalformed)
+		return KindTokenMalformed.New(err.Error())
 	case containsAny(msg, "account is suspended", "account suspended", "user account is suspended"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusForbidden).
-			WithTextCode(TextCodeAccountSuspended)
+		return KindAccountSuspended.New(err.Error())
 	case containsAny(msg, "account is disabled", "account disabled", "user account is disabled"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusForbidden).
-			WithTextCode(TextCodeAccountDisabled)
+		return KindAccountDisabled.New(err.Error())
 	case containsAny(msg, "account is archived", "account archived", "user account is archived"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusForbidden).
-			WithTextCode(TextCodeAccountArchived)
+		return KindAccountArchived.New(err.Error())
 	case containsAny(msg, "account is pending", "account pending", "user account is pending"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusForbidden).
-			WithTextCode(TextCodeAccountPending)
+		return KindAccountPending.New(err.Error())
 	case containsAny(msg, "unauthorized", "authentication"):
-		return New(err.Error(), CategoryAuth).
-			WithCode(http.StatusUnauthorized).
-			WithTextCode("UNAUTHORIZED")
+		return KindUnauthorized.New(err.Error())
 	case containsAny(msg, "forbidden", "authorization"):
-		return New(err.Error(), CategoryAuthz).
-			WithCode(http.StatusForbidden).
-			WithTextCode("FORBIDDEN")
+		return KindForbidden.New(err.Error())
 	}
 	return nil
 }
 
-// HTTPStatusToCategory maps HTTP status codes to error categories
+// HTTPStatusToCategory maps HTTP status codes to error categories, see
+// CategoryRegistry.CategoryForHTTPStatus
 func HTTPStatusToCategory(code int) Category {
-	switch {
-	case code == http.StatusNotFound:
-		return CategoryNotFound
-	case code == http.StatusUnauthorized:
-		return CategoryAuth
-	case code == http.StatusForbidden:
-		return CategoryAuthz
-	case code == http.StatusConflict:
-		return CategoryConflict
-	case code == http.StatusTooManyRequests:
-		return CategoryRateLimit
-	case code == http.StatusMethodNotAllowed:
-		return CategoryMethodNotAllowed
-	case code >= 400 && code < 500:
-		return CategoryBadInput
-	case code >= 500:
-		return CategoryInternal
-	default:
-		return CategoryInternal
-	}
+	return DefaultCategoryRegistry.CategoryForHTTPStatus(code)
 }
 
 // HTTPStatusToTextCode generates text codes from HTTP status codes
@@ -0,0 +1,250 @@
+package errors
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Mode selects the response policy used by ToErrorResponse
+type Mode string
+
+const (
+	ModeProduction  Mode = "production"
+	ModeDevelopment Mode = "development"
+)
+
+// CurrentMode returns ModeDevelopment when IsDevelopment is set
+func CurrentMode() Mode {
+	if IsDevelopment {
+		return ModeDevelopment
+	}
+	return ModeProduction
+}
+
+// SetMode sets IsDevelopment from mode
+func SetMode(mode Mode) {
+	IsDevelopment = mode == ModeDevelopment
+}
+
+// ResponseRules controls what an error response exposes
+type ResponseRules struct {
+	// ExposeMessage keeps the internal Message, otherwise clients get
+	// the public message (see GetPublicMessage)
+	ExposeMessage bool
+	// ExposeSource keeps the wrapped errors
+	ExposeSource bool
+	// ExposeLocation keeps the creation location
+	ExposeLocation bool
+	// ExposeStackTrace keeps the stack trace when the caller asks for it
+	ExposeStackTrace bool
+	// ExposeMetadata keeps the metadata
+	ExposeMetadata bool
+	// ExposeInternalMetadata keeps the InternalMetadataKeys entries when
+	// metadata is exposed
+	ExposeInternalMetadata bool
+	// CorrelationID makes sure the response has a request ID that can be
+	// matched with the server logs
+	CorrelationID bool
+}
+
+// ResponsePolicy holds the rules applied by ToErrorResponse
+type ResponsePolicy struct {
+	// Server applies to errors matched by IsServerError
+	Server ResponseRules
+	// Client applies to every other error
+	Client ResponseRules
+	// IsServerError selects the errors that get the Server rules,
+	// defaults to IsServerError
+	IsServerError func(*Error) bool
+	// NewCorrelationID creates a correlation ID for errors without a
+	// request ID, defaults to a random hex string
+	NewCorrelationID func(*Error) string
+}
+
+// ProductionResponsePolicy strips server errors down to a generic
+// message and a correlation ID. Client errors keep their metadata and
+// validation errors but only expose the public message, the wrapped
+// errors, location and stack trace are internal
+func ProductionResponsePolicy() ResponsePolicy {
+	return ResponsePolicy{
+		Server: ResponseRules{
+			CorrelationID: true,
+		},
+		Client: ResponseRules{
+			ExposeMetadata: true,
+		},
+	}
+}
+
+// DevelopmentResponsePolicy exposes every detail
+func DevelopmentResponsePolicy() ResponsePolicy {
+	all := ResponseRules{
+		ExposeMessage:          true,
+		ExposeSource:           true,
+		ExposeLocation:         true,
+		ExposeStackTrace:       true,
+		ExposeMetadata:         true,
+		ExposeInternalMetadata: true,
+	}
+	return ResponsePolicy{Server: all, Client: all}
+}
+
+// InternalMetadataKeys are metadata keys describing our own topology,
+// they are dropped from responses unless ExposeInternalMetadata is set
+var InternalMetadataKeys = []string{
+	MetadataUpstreamHost, MetadataUpstreamMethod, MetadataUpstreamPath, MetadataUpstreamStatus,
+}
+
+var responsePolicies = map[Mode]ResponsePolicy{
+	ModeProduction:  ProductionResponsePolicy(),
+	ModeDevelopment: DevelopmentResponsePolicy(),
+}
+
+// SetResponsePolicy replaces the policy used in mode.
+// Meant to be called during startup
+//
+//	policy := errors.ProductionResponsePolicy()
+//	policy.Server.ExposeMetadata = true
+//	errors.SetResponsePolicy(errors.ModeProduction, policy)
+func SetResponsePolicy(mode Mode, policy ResponsePolicy) {
+	responsePolicies[mode] = policy
+}
+
+// GetResponsePolicy returns the policy used in mode, unknown modes get
+// the production policy
+func GetResponsePolicy(mode Mode) ResponsePolicy {
+	if policy, ok := responsePolicies[mode]; ok {
+		return policy
+	}
+	return ProductionResponsePolicy()
+}
+
+// IsServerError returns true for 5xx errors and for internal errors
+// without an HTTP code
+func IsServerError(e *Error) bool {
+	if e == nil {
+		return false
+	}
+	if e.Code != 0 {
+		return e.Code >= http.StatusInternalServerError
+	}
+	return e.Category == CategoryInternal
+}
+
+// rulesFor returns the rules matching e
+func (p ResponsePolicy) rulesFor(e *Error) ResponseRules {
+	isServerError := p.IsServerError
+	if isServerError == nil {
+		isServerError = IsServerError
+	}
+	if isServerError(e) {
+		return p.Server
+	}
+	return p.Client
+}
+
+// apply strips the response error according to the policy, e must be a copy
+func (p ResponsePolicy) apply(e *Error, includeStack bool) {
+	rules := p.rulesFor(e)
+
+	rules.strip(e, includeStack)
+	if !rules.ExposeSource {
+		// field errors held by the wrapped errors are client facing
+		e.ValidationErrors = e.AllValidationErrors()
+		e.Source = nil
+	} else {
+		e.Source = rules.stripCause(e.Source, includeStack)
+	}
+
+	if rules.CorrelationID && e.RequestID == "" {
+		newID := p.NewCorrelationID
+		if newID == nil {
+			newID = newCorrelationID
+		}
+		e.RequestID = newID(e)
+	}
+}
+
+// strip removes the details of e the rules do not expose, e must be a copy
+func (r ResponseRules) strip(e *Error, includeStack bool) {
+	if !r.ExposeMessage {
+		e.Message = e.GetPublicMessage()
+		e.PublicMessage = ""
+	}
+	if !r.ExposeLocation {
+		e.Location = nil
+	}
+	if !r.ExposeStackTrace || !includeStack {
+		e.StackTrace = nil
+	}
+	if !r.ExposeMetadata {
+		e.Metadata = nil
+	} else if !r.ExposeInternalMetadata {
+		for _, key := range InternalMetadataKeys {
+			delete(e.Metadata, key)
+		}
+	}
+}
+
+// stripCause returns a copy of the wrapped error tree with the rules
+// applied to every rich error. When messages are not exposed the text of
+// foreign errors is internal, they are dropped and only the rich errors
+// they wrap are kept
+func (r ResponseRules) stripCause(err error, includeStack bool) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *Error:
+		clone := e.outputClone()
+		r.strip(clone, includeStack)
+		clone.Source = r.stripCause(e.Source, includeStack)
+		return clone
+	case *MultiError:
+		clone := e.Clone()
+		if clone.BaseError != nil {
+			clone.BaseError = e.BaseError.outputClone()
+			r.strip(clone.BaseError, includeStack)
+			clone.BaseError.Source = r.stripCause(e.BaseError.Source, includeStack)
+		}
+		clone.Causes = r.stripCauses(e.Causes, includeStack)
+		return clone
+	case *RetryableError:
+		if e.BaseError == nil {
+			return e
+		}
+		base := e.BaseError.outputClone()
+		r.strip(base, includeStack)
+		base.Source = r.stripCause(e.BaseError.Source, includeStack)
+		return &RetryableError{BaseError: base, retryable: e.retryable, baseDelay: e.baseDelay}
+	case interface{ Unwrap() []error }:
+		causes := r.stripCauses(e.Unwrap(), includeStack)
+		if len(causes) == 0 {
+			return nil
+		}
+		return Join(causes...)
+	}
+
+	if r.ExposeMessage {
+		return err
+	}
+	return r.stripCause(Unwrap(err), includeStack)
+}
+
+func (r ResponseRules) stripCauses(errs []error, includeStack bool) []error {
+	causes := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if cause := r.stripCause(err, includeStack); cause != nil {
+			causes = append(causes, cause)
+		}
+	}
+	return causes
+}
+
+func newCorrelationID(*Error) string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
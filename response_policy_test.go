@@ -0,0 +1,225 @@
+package errors_test
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/goliatone/go-errors"
+)
+
+func withMode(t *testing.T, mode errors.Mode) {
+	t.Helper()
+	previous := errors.CurrentMode()
+	errors.SetMode(mode)
+	t.Cleanup(func() { errors.SetMode(previous) })
+}
+
+func newServerError() *errors.Error {
+	return errors.Wrap(stderrors.New("pq: connection reset by peer"), errors.CategoryInternal, "load orders for tenant 42").
+		WithCode(500).
+		WithMetadata(map[string]any{"tenant": 42}).
+		WithStackTrace()
+}
+
+func TestMode(t *testing.T) {
+	withMode(t, errors.ModeDevelopment)
+	if !errors.IsDevelopment || errors.CurrentMode() != errors.ModeDevelopment {
+		t.Error("Expected SetMode to set IsDevelopment")
+	}
+
+	errors.IsDevelopment = false
+	if errors.CurrentMode() != errors.ModeProduction {
+		t.Error("Expected production mode when IsDevelopment is false")
+	}
+}
+
+func TestToErrorResponse_ProductionServerError(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	err := newServerError()
+	response := err.ToErrorResponse(true, err.StackTrace).Error
+
+	if response.Message != errors.CategoryPublicMessage(errors.CategoryInternal) {
+		t.Errorf("Expected generic message, got %q", response.Message)
+	}
+	if response.Source != nil || response.Location != nil || response.StackTrace != nil || response.Metadata != nil {
+		t.Error("Expected source, location, stack trace and metadata to be stripped")
+	}
+	if len(response.RequestID) != 32 {
+		t.Errorf("Expected a generated correlation ID, got %q", response.RequestID)
+	}
+	if err.RequestID != "" || err.Metadata == nil || err.Source == nil {
+		t.Error("Expected the original error to be unchanged")
+	}
+
+	withRequestID := newServerError().WithRequestID("req-123")
+	if got := withRequestID.ToErrorResponse(false, nil).Error.RequestID; got != "req-123" {
+		t.Errorf("Expected the request ID to be used as correlation ID, got %q", got)
+	}
+
+	// an explicit public message is safe to show
+	public := newServerError().WithPublicMessage("Orders are temporarily unavailable")
+	if got := public.ToErrorResponse(false, nil).Error.Message; got != "Orders are temporarily unavailable" {
+		t.Errorf("Expected public message, got %q", got)
+	}
+}
+
+func TestToErrorResponse_ProductionClientError(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	err := errors.New("email jane@example.com already taken", errors.CategoryConflict).
+		WithCode(409).
+		WithMetadata(map[string]any{"field": "email"})
+	response := err.ToErrorResponse(false, nil).Error
+
+	if response.Message != errors.CategoryPublicMessage(errors.CategoryConflict) {
+		t.Errorf("Expected public message, got %q", response.Message)
+	}
+	if response.Metadata["field"] != "email" {
+		t.Error("Expected client errors to keep metadata")
+	}
+	if response.Location != nil {
+		t.Error("Expected client errors to lose their location")
+	}
+	if response.RequestID != "" {
+		t.Error("Expected no correlation ID for client errors")
+	}
+}
+
+func TestToErrorResponse_ProductionClientErrorJSON(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	duplicate := errors.New("pq: duplicate key users_email_key secret@x.com", errors.CategoryConflict)
+	wrapped := errors.Wrap(duplicate, errors.CategoryConflict, "create user").WithCode(409)
+	foreign := errors.Wrap(stderrors.New("sql: SELECT * FROM users WHERE password='x'"), errors.CategoryBadInput, "lookup").
+		WithCode(400).
+		WithStackTrace()
+
+	for _, err := range []*errors.Error{wrapped, foreign} {
+		data, jsonErr := json.Marshal(err.ToErrorResponse(true, err.StackTrace))
+		if jsonErr != nil {
+			t.Fatalf("Unexpected error: %v", jsonErr)
+		}
+
+		body := string(data)
+		for _, member := range []string{`"causes"`, `"source"`, `"location"`, `"stack_trace"`} {
+			if strings.Contains(body, member) {
+				t.Errorf("Expected no %s in production client responses, got %s", member, body)
+			}
+		}
+		if strings.Contains(body, "secret@x.com") || strings.Contains(body, "password") {
+			t.Errorf("Expected internal messages to be stripped, got %s", body)
+		}
+	}
+}
+
+func TestToErrorResponse_ProductionWrappedOzzo(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	ozzoErr := validation.Errors{"email": validation.NewError("validation_required", "cannot be blank")}
+	err := errors.Wrap(ozzoErr, errors.CategoryValidation, "invalid signup")
+
+	response := err.ToErrorResponse(false, nil)
+	if response.Error.Source != nil {
+		t.Error("Expected source to be stripped")
+	}
+	fields := response.Error.ValidationErrors
+	if len(fields) != 1 || fields[0].Field != "email" || fields[0].Message != "cannot be blank" {
+		t.Errorf("Expected the ozzo field errors in the response, got %v", fields)
+	}
+	if len(err.ValidationErrors) != 0 {
+		t.Error("Expected the original error to be unchanged")
+	}
+}
+
+func TestToErrorResponse_Development(t *testing.T) {
+	withMode(t, errors.ModeDevelopment)
+
+	err := newServerError().WithPublicMessage("Orders are unavailable")
+	response := err.ToErrorResponse(true, err.StackTrace).Error
+
+	if response.Message != err.Message || response.PublicMessage != err.PublicMessage {
+		t.Errorf("Expected full messages, got %q / %q", response.Message, response.PublicMessage)
+	}
+	if response.Source == nil || response.Location == nil || len(response.StackTrace) == 0 || response.Metadata == nil {
+		t.Error("Expected development responses to keep every detail")
+	}
+
+	if len(err.ToErrorResponse(false, nil).Error.StackTrace) != 0 {
+		t.Error("Expected the stack trace to require includeStack")
+	}
+}
+
+func TestSetResponsePolicy(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+	original := errors.GetResponsePolicy(errors.ModeProduction)
+	t.Cleanup(func() { errors.SetResponsePolicy(errors.ModeProduction, original) })
+
+	policy := errors.ProductionResponsePolicy()
+	policy.Server.ExposeMetadata = true
+	policy.NewCorrelationID = func(*errors.Error) string { return "fixed" }
+	policy.IsServerError = func(e *errors.Error) bool {
+		return errors.IsServerError(e) || e.Category == errors.CategoryExternal
+	}
+	errors.SetResponsePolicy(errors.ModeProduction, policy)
+
+	response := newServerError().ToErrorResponse(false, nil).Error
+	if response.Metadata["tenant"] != 42 || response.Source != nil {
+		t.Error("Expected only the overridden rule to change")
+	}
+	if response.RequestID != "fixed" {
+		t.Errorf("Expected custom correlation ID, got %q", response.RequestID)
+	}
+
+	external := errors.New("stripe timeout", errors.CategoryExternal).WithCode(424)
+	if got := external.ToErrorResponse(false, nil).Error.RequestID; got != "fixed" {
+		t.Error("Expected the custom server error check to be used")
+	}
+}
+
+func TestErrorCollector_ToErrorResponsePolicy(t *testing.T) {
+	withMode(t, errors.ModeProduction)
+
+	collector := errors.NewCollector()
+	collector.Add(newServerError())
+	collector.Add(errors.New("disk full on /var/lib/db", errors.CategoryInternal))
+
+	response := collector.ToErrorResponse(false)
+	if response.Error.Metadata != nil || response.Error.RequestID == "" {
+		t.Error("Expected merged server error response to be stripped with a correlation ID")
+	}
+	if response.Error.Message != "Multiple errors occurred" {
+		t.Errorf("Expected merged public message, got %q", response.Error.Message)
+	}
+
+	single := errors.NewCollector()
+	single.Add(newServerError())
+	if single.ToErrorResponse(false).Error.Source != nil {
+		t.Error("Expected single error response to follow the policy")
+	}
+}
+
+func TestIsServerError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *errors.Error
+		want bool
+	}{
+		{"5xx", errors.New("x", errors.CategoryExternal).WithCode(502), true},
+		{"4xx internal", errors.New("x", errors.CategoryInternal).WithCode(400), false},
+		{"internal without code", errors.New("x", errors.CategoryInternal), true},
+		{"validation without code", errors.New("x", errors.CategoryValidation), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.IsServerError(tt.err); got != tt.want {
+				t.Errorf("Expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
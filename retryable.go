@@ -1,6 +1,10 @@
 package errors
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type BaseError = Error
 
@@ -62,45 +66,74 @@ func (r *RetryableError) RetryDealy(attempt int) time.Duration {
 
 // WithRetryable sets whether this error should be retryable
 func (r *RetryableError) WithRetryable(retryable bool) *RetryableError {
+	r = r.mutable()
 	r.retryable = retryable
 	return r
 }
 
 // WithRetryDelay sets the base delay for retry attempts
 func (r *RetryableError) WithRetryDelay(delay time.Duration) *RetryableError {
+	r = r.mutable()
 	r.baseDelay = delay
 	return r
 }
 
 func (r *RetryableError) WithMetadata(metas ...map[string]any) *RetryableError {
-	r.BaseError.WithMetadata(metas...)
+	r = r.mutable()
+	if r.BaseError != nil {
+		r.BaseError.WithMetadata(metas...)
+	}
 	return r
 }
 
 func (r *RetryableError) WithStackTrace() *RetryableError {
-	r.BaseError.WithStackTrace()
+	r = r.mutable()
+	if r.BaseError != nil {
+		r.BaseError.WithStackTrace()
+	}
 	return r
 }
 
 func (r *RetryableError) WithCode(code int) *RetryableError {
-	r.BaseError.WithCode(code)
+	r = r.mutable()
+	if r.BaseError != nil {
+		r.BaseError.WithCode(code)
+	}
 	return r
 }
 
 func (r *RetryableError) WithTextCode(code string) *RetryableError {
-	r.BaseError.TextCode = code
+	r = r.mutable()
+	if r.BaseError != nil {
+		r.BaseError.TextCode = code
+	}
+	return r
+}
+
+// WithPublicMessage sets the message shown to API clients
+func (r *RetryableError) WithPublicMessage(message string) *RetryableError {
+	r = r.mutable()
+	if r.BaseError != nil {
+		r.BaseError.PublicMessage = message
+	}
 	return r
 }
 
 // WithLocation sets the location where the error occurred
 func (r *RetryableError) WithLocation(loc *ErrorLocation) *RetryableError {
-	r.BaseError.WithLocation(loc)
+	r = r.mutable()
+	if r.BaseError != nil {
+		r.BaseError.WithLocation(loc)
+	}
 	return r
 }
 
 // WithSeverity sets the severity level of the retryable error
 func (r *RetryableError) WithSeverity(s Severity) *RetryableError {
-	r.BaseError.WithSeverity(s)
+	r = r.mutable()
+	if r.BaseError != nil {
+		r.BaseError.WithSeverity(s)
+	}
 	return r
 }
 
@@ -147,15 +180,91 @@ func NewRetryableExternal(message string) *RetryableError {
 	return NewRetryable(message, CategoryExternal).
 		WithRetryDelay(2 * time.Second).
 		WithCode(502).
-		WithTextCode("EXTERNAL_SERVICE_ERROR")
+		WithTextCode(TextCodeExternalServiceError)
 }
 
 // IsRetryableError checks if an error implements the IsRetryable interface
-// and returns true
+// and returns true. Other rich errors are never retryable from
+// SeverityCritical up, below it errors created from a Kind use its
+// Retryable flag and the rest the flag of their category, so plain
+// rate_limit errors are retryable
 func IsRetryableError(err error) bool {
 	var retryable interface{ IsRetryable() bool }
 	if As(err, &retryable) {
 		return retryable.IsRetryable()
 	}
-	return false
+	var e *Error
+	if !As(err, &e) || e.Severity >= SeverityCritical {
+		return false
+	}
+	if kind := KindOf(err); kind != nil {
+		return kind.Retryable
+	}
+	return DefaultCategoryRegistry.Definition(e.Category).Retryable
+}
+
+// MarshalJSON implements JSON marshaling for RetryableError, the error
+// fields are written with the retryable flag and base delay
+func (r *RetryableError) MarshalJSON() ([]byte, error) {
+	if redactionPolicy.JSON != nil {
+		r = redactionPolicy.JSON.RedactError(r).(*RetryableError)
+	}
+	return r.marshalJSON()
+}
+
+func (r *RetryableError) marshalJSON() ([]byte, error) {
+	base := r.BaseError
+	if base == nil {
+		base = &Error{}
+	}
+
+	aux, err := base.toJSONTree()
+	if err != nil {
+		return nil, err
+	}
+	retryable := r.retryable
+	aux.Retryable = &retryable
+	if r.baseDelay > 0 {
+		aux.RetryDelay = r.baseDelay.String()
+	}
+
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON implements JSON unmarshaling for RetryableError
+func (r *RetryableError) UnmarshalJSON(data []byte) error {
+	var aux errorJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return r.fromJSON(aux)
+}
+
+func (r *RetryableError) fromJSON(aux errorJSON) error {
+	base := &Error{}
+	causes, err := base.fromJSON(aux)
+	if err != nil {
+		return err
+	}
+	switch len(causes) {
+	case 0:
+	case 1:
+		base.Source = causes[0]
+	default:
+		base.Source = Join(causes...)
+	}
+
+	var delay time.Duration
+	if aux.RetryDelay != "" {
+		if delay, err = time.ParseDuration(aux.RetryDelay); err != nil {
+			return fmt.Errorf("invalid retry delay: %w", err)
+		}
+	}
+
+	*r = RetryableError{
+		BaseError: base,
+		retryable: aux.Retryable != nil && *aux.Retryable,
+		baseDelay: delay,
+	}
+	return nil
 }
@@ -0,0 +1,272 @@
+package errors
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// JSONSchemaDialect is the JSON Schema dialect used by ErrorResponseJSONSchema
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaExampleTimestamp keeps generated examples stable
+const schemaExampleTimestamp = "2024-01-01T12:00:00Z"
+
+// ErrorResponseJSONSchema returns a JSON Schema document describing the
+// ErrorResponse envelope as produced by Error.MarshalJSON
+func ErrorResponseJSONSchema() map[string]any {
+	schema := errorResponseSchema("#/$defs/")
+	schema["$schema"] = JSONSchemaDialect
+	schema["$id"] = "https://github.com/goliatone/go-errors/schemas/error-response.json"
+	schema["title"] = "ErrorResponse"
+	schema["$defs"] = errorSchemaDefinitions("#/$defs/")
+	return schema
+}
+
+// OpenAPIComponents returns OpenAPI 3.1 components/schemas and
+// components/responses for the error envelope. A response with examples
+// is added per category found in the catalog, DefaultCatalog is used
+// when catalog is nil
+func OpenAPIComponents(catalog *Catalog) map[string]any {
+	if catalog == nil {
+		catalog = DefaultCatalog
+	}
+
+	const prefix = "#/components/schemas/"
+
+	schemas := errorSchemaDefinitions(prefix)
+	schemas["ErrorResponse"] = errorResponseSchema(prefix)
+
+	return map[string]any{
+		"schemas":   schemas,
+		"responses": categoryResponses(catalog, prefix),
+	}
+}
+
+func errorResponseSchema(prefix string) map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"required":             []string{"error"},
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"error": ref(prefix, "Error"),
+		},
+	}
+}
+
+func errorSchemaDefinitions(prefix string) map[string]any {
+	severities := make([]string, 0, len(severityStrings))
+	for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+		severities = append(severities, sev.String())
+	}
+
+	return map[string]any{
+		"Error": map[string]any{
+			"type":     "object",
+			"required": []string{"category", "message", "timestamp", "severity"},
+			"properties": map[string]any{
+				"category":  map[string]any{"type": "string", "description": "High level error category"},
+				"code":      map[string]any{"type": "integer", "description": "HTTP status code"},
+				"text_code": map[string]any{"type": "string", "description": "Machine readable error code"},
+				"message":   map[string]any{"type": "string"},
+				"public_message": map[string]any{
+					"type":        "string",
+					"description": "Client facing message, responses carry it in message",
+				},
+				"source": map[string]any{"type": "string", "description": "Text of a wrapped foreign error"},
+				"causes": map[string]any{
+					"type":        "array",
+					"description": "Wrapped errors, structured when they are rich errors",
+					"items": map[string]any{
+						"oneOf": []any{ref(prefix, "Error"), map[string]any{"type": "string"}},
+					},
+				},
+				"validation_errors": map[string]any{
+					"type":  "array",
+					"items": ref(prefix, "FieldError"),
+				},
+				"metadata":    map[string]any{"type": "object", "additionalProperties": true},
+				"request_id":  map[string]any{"type": "string"},
+				"timestamp":   map[string]any{"type": "string", "format": "date-time"},
+				"stack_trace": map[string]any{"type": "array", "items": ref(prefix, "StackFrame")},
+				"location":    ref(prefix, "ErrorLocation"),
+				"severity":    map[string]any{"type": "string", "enum": severities},
+				"fingerprint": map[string]any{
+					"type":        "string",
+					"readOnly":    true,
+					"description": "Stable hash grouping occurrences of the same error",
+				},
+				"retryable": map[string]any{
+					"type":        "boolean",
+					"description": "Set when the error is retryable, only written for retryable errors",
+				},
+				"retry_delay": map[string]any{
+					"type":        "string",
+					"description": "Base retry delay as a Go duration",
+				},
+			},
+		},
+		"FieldError": map[string]any{
+			"type":     "object",
+			"required": []string{"field", "message"},
+			"properties": map[string]any{
+				"field":   map[string]any{"type": "string"},
+				"message": map[string]any{"type": "string"},
+				"value":   map[string]any{},
+				"code":    map[string]any{"type": "string", "description": "Validation rule code"},
+				"params":  map[string]any{"type": "object", "additionalProperties": true},
+				"label":   map[string]any{"type": "string", "description": "Human name of the field"},
+			},
+		},
+		"StackFrame": map[string]any{
+			"type":     "object",
+			"required": []string{"function", "file", "line"},
+			"properties": map[string]any{
+				"function": map[string]any{"type": "string"},
+				"file":     map[string]any{"type": "string"},
+				"line":     map[string]any{"type": "integer"},
+			},
+		},
+		"ErrorLocation": map[string]any{
+			"type":     "object",
+			"required": []string{"file", "line", "function"},
+			"properties": map[string]any{
+				"file":     map[string]any{"type": "string"},
+				"line":     map[string]any{"type": "integer"},
+				"function": map[string]any{"type": "string"},
+			},
+		},
+	}
+}
+
+// categoryResponses builds one response per category with an example per
+// text code. Registered categories without a text code get an example
+// built from their definition
+func categoryResponses(catalog *Catalog, prefix string) map[string]any {
+	byCategory := make(map[Category][]CodeDefinition)
+	for _, def := range catalog.Definitions() {
+		if def.Category == "" {
+			continue
+		}
+		byCategory[def.Category] = append(byCategory[def.Category], def)
+	}
+
+	responses := make(map[string]any, len(byCategory))
+	for category, defs := range byCategory {
+		examples := make(map[string]any, len(defs))
+		for _, def := range defs {
+			message := def.Description
+			if message == "" {
+				message = http.StatusText(def.Status)
+			}
+
+			errorExample := map[string]any{
+				"category":  category.String(),
+				"text_code": def.TextCode,
+				"message":   message,
+				"timestamp": schemaExampleTimestamp,
+				"severity":  SeverityError.String(),
+			}
+			if def.Status != 0 {
+				errorExample["code"] = def.Status
+			}
+
+			example := map[string]any{
+				"value": map[string]any{"error": errorExample},
+			}
+			if def.Description != "" {
+				example["summary"] = def.Description
+			}
+			examples[def.TextCode] = example
+		}
+
+		responses[responseName(category)] = categoryResponse(describeCategoryResponse(category, defs), examples, prefix)
+	}
+
+	for _, category := range DefaultCategoryRegistry.Categories() {
+		if _, ok := byCategory[category]; ok {
+			continue
+		}
+		def := DefaultCategoryRegistry.Definition(category)
+
+		message := def.PublicMessage
+		if message == "" {
+			message = http.StatusText(def.HTTPStatus)
+		}
+		example := map[string]any{
+			"value": map[string]any{"error": map[string]any{
+				"category":  category.String(),
+				"code":      def.HTTPStatus,
+				"message":   message,
+				"timestamp": schemaExampleTimestamp,
+				"severity":  def.Severity.String(),
+			}},
+		}
+		if def.Description != "" {
+			example["summary"] = def.Description
+		}
+
+		description := describeCategoryResponse(category, []CodeDefinition{{Status: def.HTTPStatus}})
+		responses[responseName(category)] = categoryResponse(description, map[string]any{category.String(): example}, prefix)
+	}
+	return responses
+}
+
+func categoryResponse(description string, examples map[string]any, prefix string) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema":   ref(prefix, "ErrorResponse"),
+				"examples": examples,
+			},
+		},
+	}
+}
+
+// describeCategoryResponse lists the HTTP statuses used by the category
+func describeCategoryResponse(category Category, defs []CodeDefinition) string {
+	seen := make(map[int]bool)
+	var statuses []int
+	for _, def := range defs {
+		if def.Status != 0 && !seen[def.Status] {
+			seen[def.Status] = true
+			statuses = append(statuses, def.Status)
+		}
+	}
+	sort.Ints(statuses)
+
+	desc := "Error response for the " + category.String() + " category"
+	if len(statuses) == 0 {
+		return desc
+	}
+
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = strconv.Itoa(status)
+		if text := http.StatusText(status); text != "" {
+			parts[i] = text + " (" + parts[i] + ")"
+		}
+	}
+	return desc + ": " + strings.Join(parts, ", ")
+}
+
+// responseName converts a category such as not_found into NotFoundError
+func responseName(category Category) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(category.String(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	b.WriteString("Error")
+	return b.String()
+}
+
+func ref(prefix, name string) map[string]any {
+	return map[string]any{"$ref": prefix + name}
+}
@@ -0,0 +1,119 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/goliatone/go-errors"
+)
+
+func TestErrorResponseJSONSchema(t *testing.T) {
+	schema := errors.ErrorResponseJSONSchema()
+
+	if schema["$schema"] != errors.JSONSchemaDialect {
+		t.Errorf("Expected dialect %s, got %v", errors.JSONSchemaDialect, schema["$schema"])
+	}
+
+	defs := schema["$defs"].(map[string]any)
+	errorProps := defs["Error"].(map[string]any)["properties"].(map[string]any)
+
+	severity := errorProps["severity"].(map[string]any)
+	if severity["type"] != "string" {
+		t.Errorf("Expected severity to be a string, got %v", severity["type"])
+	}
+	if enum := severity["enum"].([]string); !slices.Contains(enum, "CRITICAL") || len(enum) != 6 {
+		t.Errorf("Expected all severities in the enum, got %v", enum)
+	}
+
+	items := errorProps["validation_errors"].(map[string]any)["items"].(map[string]any)
+	if items["$ref"] != "#/$defs/FieldError" {
+		t.Errorf("Expected validation_errors items to reference FieldError, got %v", items["$ref"])
+	}
+
+	// every field emitted by MarshalJSON must be described
+	err := errors.New("boom", errors.CategoryValidation).
+		WithCode(400).
+		WithTextCode("BAD").
+		WithMetadata(map[string]any{"k": "v"}).
+		WithRequestID("req-1").
+		WithStackTrace()
+	err.ValidationErrors = errors.ValidationErrors{{Field: "email", Message: "required", Value: ""}}
+	err.Source = errors.New("inner", errors.CategoryInternal)
+
+	data, _ := json.Marshal(err.ToErrorResponse(true, err.StackTrace))
+	var envelope map[string]map[string]any
+	if jsonErr := json.Unmarshal(data, &envelope); jsonErr != nil {
+		t.Fatalf("Unexpected error: %v", jsonErr)
+	}
+	for key := range envelope["error"] {
+		if _, ok := errorProps[key]; !ok {
+			t.Errorf("Expected schema to describe field %q", key)
+		}
+	}
+}
+
+func TestOpenAPIComponents(t *testing.T) {
+	catalog := errors.NewCatalog()
+	catalog.MustRegister(
+		errors.CodeDefinition{TextCode: "USER_NOT_FOUND", Category: errors.CategoryNotFound, Status: 404, Description: "User not found"},
+		errors.CodeDefinition{TextCode: "ORDER_NOT_FOUND", Category: errors.CategoryNotFound, Status: 404},
+		errors.CodeDefinition{TextCode: "RATE_LIMITED", Category: errors.CategoryRateLimit, Status: 429},
+	)
+
+	billing := errors.Category("billing_hold")
+	errors.MustRegisterCategory(errors.NewCategoryDefinition(billing).
+		WithHTTPStatus(http.StatusPaymentRequired).
+		WithPublicMessage("Payment is required"))
+	t.Cleanup(func() { errors.DefaultCategoryRegistry.Unregister(billing) })
+
+	components := errors.OpenAPIComponents(catalog)
+
+	schemas := components["schemas"].(map[string]any)
+	for _, name := range []string{"ErrorResponse", "Error", "FieldError", "StackFrame", "ErrorLocation"} {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("Expected schema %s", name)
+		}
+	}
+
+	responses := components["responses"].(map[string]any)
+	if len(responses) != len(errors.DefaultCategoryRegistry.Categories()) {
+		t.Fatalf("Expected a response per registered category, got %d", len(responses))
+	}
+
+	hold, ok := responses["BillingHoldError"].(map[string]any)
+	if !ok {
+		t.Fatal("Expected a response for a category without text codes")
+	}
+	if hold["description"] != "Error response for the billing_hold category: Payment Required (402)" {
+		t.Errorf("Unexpected description: %v", hold["description"])
+	}
+	holdExample := hold["content"].(map[string]any)["application/json"].(map[string]any)["examples"].(map[string]any)["billing_hold"].(map[string]any)["value"].(map[string]any)["error"].(map[string]any)
+	if holdExample["code"] != http.StatusPaymentRequired || holdExample["message"] != "Payment is required" {
+		t.Errorf("Unexpected example: %v", holdExample)
+	}
+
+	notFound, ok := responses["NotFoundError"].(map[string]any)
+	if !ok {
+		t.Fatal("Expected NotFoundError response")
+	}
+	if notFound["description"] != "Error response for the not_found category: Not Found (404)" {
+		t.Errorf("Unexpected description: %v", notFound["description"])
+	}
+
+	media := notFound["content"].(map[string]any)["application/json"].(map[string]any)
+	if media["schema"].(map[string]any)["$ref"] != "#/components/schemas/ErrorResponse" {
+		t.Errorf("Expected ErrorResponse schema reference, got %v", media["schema"])
+	}
+
+	examples := media["examples"].(map[string]any)
+	example := examples["ORDER_NOT_FOUND"].(map[string]any)["value"].(map[string]any)["error"].(map[string]any)
+	if example["message"] != "Not Found" || example["code"] != 404 || example["category"] != "not_found" {
+		t.Errorf("Unexpected example: %v", example)
+	}
+
+	if _, ok := errors.OpenAPIComponents(nil)["responses"].(map[string]any)["AuthenticationError"]; !ok {
+		t.Error("Expected default catalog responses when catalog is nil")
+	}
+}
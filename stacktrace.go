@@ -29,19 +29,19 @@ func CaptureStackTrace(skip int) StackTrace {
 	pcs := make([]uintptr, 32)
 	n := runtime.Callers(skip+2, pcs)
 
-	for i := range n {
-		pc := pcs[i]
-		fn := runtime.FuncForPC(pc)
-		if fn == nil {
-			continue
+	callers := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := callers.Next()
+		if frame.Function != "" {
+			frames = append(frames, StackFrame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+		if !more {
+			break
 		}
-
-		file, line := fn.FileLine(pc)
-		frames = append(frames, StackFrame{
-			Function: fn.Name(),
-			File:     file,
-			Line:     line,
-		})
 	}
 	return frames
 }
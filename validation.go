@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 // FieldError reprents a single validation error for a given field
@@ -11,6 +13,12 @@ type FieldError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
 	Value   any    `json:"value,omitempty"`
+	// Code is the validation rule code, used as localization key
+	Code string `json:"code,omitempty"`
+	// Params are the rule parameters, available as named message params
+	Params map[string]any `json:"params,omitempty"`
+	// Label is the human name of the field, set by HumanizeFieldError
+	Label string `json:"label,omitempty"`
 }
 
 func (e FieldError) Error() string {
@@ -39,7 +47,7 @@ func NewValidation(message string, fieldErrors ...FieldError) *Error {
 		ValidationErrors: fieldErrors,
 		Timestamp:        time.Now(),
 		Location:         captureLocation(1),
-		Severity:         SeverityError,
+		Severity:         categorySeverity(CategoryValidation),
 	}
 }
 
@@ -57,7 +65,7 @@ func NewValidationFromMap(message string, fieldMap map[string]string) *Error {
 		ValidationErrors: fieldErrors,
 		Timestamp:        time.Now(),
 		Location:         captureLocation(1),
-		Severity:         SeverityError,
+		Severity:         categorySeverity(CategoryValidation),
 	}
 }
 
@@ -77,21 +85,53 @@ func NewValidationFromGroups(message string, groups map[string][]string) *Error
 		ValidationErrors: fieldErrors,
 		Timestamp:        time.Now(),
 		Location:         captureLocation(1),
-		Severity:         SeverityError,
+		Severity:         categorySeverity(CategoryValidation),
 	}
 }
 
+// GetValidationErrors collects the validation errors of every rich
+// error in the tree of err, including all branches of joined errors
 func GetValidationErrors(err error) (ValidationErrors, bool) {
+	allErrors := collectValidationErrors(err)
+	return allErrors, len(allErrors) > 0
+}
+
+// collectValidationErrors gathers the field errors of the rich errors
+// and ozzo validation errors found in the tree of err, without duplicates
+func collectValidationErrors(err error) ValidationErrors {
 	var allErrors ValidationErrors
-	found := false
-	currentErr := err
-	for currentErr != nil {
-		var e *Error
-		if As(currentErr, &e) && len(e.ValidationErrors) > 0 {
-			allErrors = append(allErrors, e.ValidationErrors...)
-			found = true
+	Walk(err, func(node error, _ int) bool {
+		if validationErrors, ok := node.(validation.Errors); ok {
+			allErrors = appendUniqueFieldErrors(allErrors, fromOzzoFieldErrors(validationErrors)...)
+		} else if e := richErrorOf(node); e != nil {
+			allErrors = appendUniqueFieldErrors(allErrors, e.ValidationErrors...)
 		}
-		currentErr = Unwrap(currentErr)
+		return true
+	})
+	return allErrors
+}
+
+// appendUniqueFieldErrors appends the field errors that are not
+// already present in dst. Errors only count as duplicates when every
+// field matches, so distinct rules or values on a field are kept
+func appendUniqueFieldErrors(dst ValidationErrors, fieldErrors ...FieldError) ValidationErrors {
+	seen := make(map[string]bool, len(dst)+len(fieldErrors))
+	for _, existing := range dst {
+		seen[fieldErrorKey(existing)] = true
 	}
-	return allErrors, found
+	for _, fieldErr := range fieldErrors {
+		key := fieldErrorKey(fieldErr)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		dst = append(dst, fieldErr)
+	}
+	return dst
+}
+
+// fieldErrorKey identifies a field error, maps are printed with sorted
+// keys so equal params give equal keys
+func fieldErrorKey(e FieldError) string {
+	return fmt.Sprintf("%q|%q|%q|%q|%v|%v", e.Field, e.Message, e.Code, e.Label, e.Value, e.Params)
 }
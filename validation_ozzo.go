@@ -25,7 +25,7 @@ func FromOzzoValidation(err error, message string) *Error {
 		Source:    err,
 		Timestamp: time.Now(),
 		Location:  captureLocation(1),
-		Severity:  SeverityError,
+		Severity:  categorySeverity(CategoryValidation),
 	}
 }
 
@@ -36,16 +36,10 @@ func fromOzzoValidationErrors(validationErrors validation.Errors, message string
 		if nestedErrors, ok := fieldErr.(validation.Errors); ok {
 			for nestedField, nestedErr := range nestedErrors {
 				fieldName := fmt.Sprintf("%s.%s", field, nestedField)
-				fieldErrors = append(fieldErrors, FieldError{
-					Field:   fieldName,
-					Message: strings.TrimSpace(nestedErr.Error()),
-				})
+				fieldErrors = append(fieldErrors, fieldErrorFromOzzo(fieldName, nestedErr))
 			}
 		} else {
-			fieldErrors = append(fieldErrors, FieldError{
-				Field:   field,
-				Message: strings.TrimSpace(fieldErr.Error()),
-			})
+			fieldErrors = append(fieldErrors, fieldErrorFromOzzo(field, fieldErr))
 		}
 	}
 
@@ -55,10 +49,26 @@ func fromOzzoValidationErrors(validationErrors validation.Errors, message string
 		ValidationErrors: fieldErrors,
 		Timestamp:        time.Now(),
 		Location:         location,
-		Severity:         SeverityError,
+		Severity:         categorySeverity(CategoryValidation),
 	}
 }
 
+// fieldErrorFromOzzo converts an ozzo rule error, keeping its code and
+// params so the message can be localized
+func fieldErrorFromOzzo(field string, err error) FieldError {
+	fieldErr := FieldError{
+		Field:   field,
+		Message: strings.TrimSpace(err.Error()),
+	}
+	if ruleErr, ok := err.(validation.Error); ok {
+		fieldErr.Code = ruleErr.Code()
+		if params := ruleErr.Params(); len(params) > 0 {
+			fieldErr.Params = params
+		}
+	}
+	return fieldErr
+}
+
 func ValidateWithOzzo(validateFunc func() error, message string) *Error {
 	if err := validateFunc(); err != nil {
 		return FromOzzoValidation(err, message)
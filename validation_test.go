@@ -169,6 +169,21 @@ func TestGetValidationErrors(t *testing.T) {
 		t.Errorf("Expected 2 validation errors, got %d", len(errs))
 	}
 
+	// Wrapped copies are reported once, distinct codes on a field are kept
+	wrapped := errors.Wrap(validationErr, errors.CategoryHandler, "create user")
+	tooLong := &errors.Error{
+		Category: errors.CategoryValidation,
+		Message:  "validation failed",
+		ValidationErrors: errors.ValidationErrors{
+			{Field: "email", Message: "invalid", Code: "validation_length_too_long"},
+			{Field: "email", Message: "invalid", Code: "validation_is_email"},
+		},
+	}
+	errs, _ = errors.GetValidationErrors(errors.Join(wrapped, validationErr, tooLong))
+	if len(errs) != 4 {
+		t.Errorf("Expected 4 validation errors, got %d: %v", len(errs), errs)
+	}
+
 	// Test with non-validation error
 	nonValidationErr := &errors.Error{
 		Category: errors.CategoryAuth,
@@ -0,0 +1,180 @@
+package errors
+
+// Walk visits err and every error it wraps depth first, following both
+// Unwrap() error and Unwrap() []error. depth is 0 for err and grows by
+// one per unwrap. The walk stops as soon as fn returns false
+func Walk(err error, fn func(err error, depth int) bool) {
+	walk(err, 0, fn)
+}
+
+func walk(err error, depth int, fn func(error, int) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	if !fn(err, depth) {
+		return false
+	}
+
+	for _, child := range unwrapAll(err) {
+		if !walk(child, depth+1, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the first error in the tree of err, in Walk order, for
+// which pred returns true
+func Find(err error, pred func(error) bool) error {
+	var found error
+	Walk(err, func(node error, _ int) bool {
+		if pred(node) {
+			found = node
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// AsType is a generic As, it returns the first error in the tree of err
+// that matches T
+//
+//	if e, ok := errors.AsType[*errors.RetryableError](err); ok {
+//		time.Sleep(e.RetryDelay(attempt))
+//	}
+func AsType[T error](err error) (T, bool) {
+	var target T
+	if err == nil {
+		return target, false
+	}
+	ok := As(err, &target)
+	return target, ok
+}
+
+// All returns every error in the tree of err that matches T, in Walk
+// order. Unlike AsType it keeps going through every branch
+func All[T error](err error) []T {
+	var matches []T
+	Walk(err, func(node error, _ int) bool {
+		if t, ok := asNode[T](node); ok {
+			matches = append(matches, t)
+		}
+		return true
+	})
+	return matches
+}
+
+// Filter returns a copy of err without the branches of joined errors,
+// including MultiError causes, that hold no error for which pred returns
+// true. It returns nil when no error in the tree matches. Rich errors
+// wrapping a pruned tree are cloned, other single error wrappers are
+// kept as they are. A joined error or MultiError that matches pred
+// while none of its branches do is kept whole
+func Filter(err error, pred func(error) bool) error {
+	if Find(err, pred) == nil {
+		return nil
+	}
+	filtered, _ := prune(err, pred)
+	return filtered
+}
+
+// prune removes the branches without a match below err, which holds at
+// least one match. It reports whether anything was removed
+func prune(err error, pred func(error) bool) (error, bool) {
+	switch e := err.(type) {
+	case *MultiError:
+		causes, changed := pruneBranches(e.Causes, pred)
+		if !changed || len(causes) == 0 {
+			return e, false
+		}
+		clone := e.Clone()
+		clone.Causes = causes
+		return clone, true
+	case *RetryableError:
+		if e.BaseError == nil {
+			return e, false
+		}
+		base, changed := prune(e.BaseError, pred)
+		if !changed {
+			return e, false
+		}
+		return &RetryableError{
+			BaseError: base.(*Error),
+			retryable: e.retryable,
+			baseDelay: e.baseDelay,
+		}, true
+	case *Error:
+		if Find(e.Source, pred) == nil {
+			return e, false
+		}
+		source, changed := prune(e.Source, pred)
+		if !changed {
+			return e, false
+		}
+		clone := e.Clone()
+		clone.Source = source
+		return clone, true
+	case interface{ Unwrap() []error }:
+		branches, changed := pruneBranches(e.Unwrap(), pred)
+		if !changed || len(branches) == 0 {
+			return err, false
+		}
+		return Join(branches...), true
+	}
+	return err, false
+}
+
+func pruneBranches(branches []error, pred func(error) bool) ([]error, bool) {
+	kept := make([]error, 0, len(branches))
+	changed := false
+	for _, branch := range branches {
+		if Find(branch, pred) == nil {
+			changed = true
+			continue
+		}
+		pruned, prunedChanged := prune(branch, pred)
+		changed = changed || prunedChanged
+		kept = append(kept, pruned)
+	}
+	return kept, changed
+}
+
+// unwrapAll returns the direct children of err, supporting both
+// Unwrap() error and Unwrap() []error
+func unwrapAll(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	case interface{ Unwrap() error }:
+		if next := u.Unwrap(); next != nil {
+			return []error{next}
+		}
+	}
+	return nil
+}
+
+// asNode matches node itself against T, without unwrapping, honoring
+// As methods such as the one of MultiError
+func asNode[T error](node error) (T, bool) {
+	if t, ok := node.(T); ok {
+		return t, true
+	}
+
+	var target T
+	if x, ok := node.(interface{ As(any) bool }); ok && x.As(&target) {
+		return target, true
+	}
+	return target, false
+}
+
+// richErrorOf returns the *Error carried by node itself, if any
+func richErrorOf(node error) *Error {
+	e, _ := asNode[*Error](node)
+	return e
+}
+
+func isRichError(node error) bool {
+	return richErrorOf(node) != nil
+}
@@ -0,0 +1,171 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/goliatone/go-errors"
+)
+
+func TestWalk(t *testing.T) {
+	plain := fmt.Errorf("plain")
+	notFound := errors.Wrap(plain, errors.CategoryNotFound, "load user")
+	auth := errors.New("denied", errors.CategoryAuth)
+	joined := fmt.Errorf("request: %w", errors.Join(notFound, auth))
+
+	var visited []string
+	errors.Walk(joined, func(err error, depth int) bool {
+		visited = append(visited, fmt.Sprintf("%d:%T", depth, err))
+		return true
+	})
+
+	want := "[0:*fmt.wrapError 1:*errors.joinError 2:*errors.Error 3:*errors.errorString 2:*errors.Error]"
+	if got := fmt.Sprint(visited); got != want {
+		t.Errorf("Expected visit order %s, got %s", want, got)
+	}
+
+	count := 0
+	errors.Walk(joined, func(err error, depth int) bool {
+		count++
+		return depth < 2
+	})
+	if count != 3 {
+		t.Errorf("Expected the walk to stop at the first rich error, visited %d", count)
+	}
+
+	errors.Walk(nil, func(error, int) bool {
+		t.Error("Expected nil to not be visited")
+		return true
+	})
+}
+
+func TestFind(t *testing.T) {
+	auth := errors.New("denied", errors.CategoryAuth)
+	joined := errors.Join(fmt.Errorf("plain"), fmt.Errorf("wrapped: %w", auth))
+
+	found := errors.Find(joined, func(err error) bool {
+		e, ok := err.(*errors.Error)
+		return ok && e.Category == errors.CategoryAuth
+	})
+	if found != auth {
+		t.Errorf("Expected to find the auth error, got %v", found)
+	}
+
+	if found := errors.Find(joined, func(error) bool { return false }); found != nil {
+		t.Errorf("Expected nil when nothing matches, got %v", found)
+	}
+}
+
+func TestAsTypeAndAll(t *testing.T) {
+	retryable := errors.NewRetryable("timeout", errors.CategoryExternal)
+	validationErr := errors.NewValidation("invalid", errors.FieldError{Field: "email", Message: "required"})
+	multi := errors.WrapAll(errors.CategoryOperation, "batch failed", retryable, fmt.Errorf("row 2: %w", validationErr))
+
+	got, ok := errors.AsType[*errors.RetryableError](multi)
+	if !ok || got != retryable {
+		t.Errorf("Expected AsType to find the retryable error, got %v", got)
+	}
+	if _, ok := errors.AsType[*errors.RetryableError](validationErr); ok {
+		t.Error("Expected AsType to fail without a retryable error")
+	}
+	if _, ok := errors.AsType[*errors.Error](nil); ok {
+		t.Error("Expected AsType to fail on nil")
+	}
+
+	all := errors.All[*errors.Error](multi)
+	if len(all) != 3 {
+		t.Fatalf("Expected base, retryable base and validation errors, got %d", len(all))
+	}
+	if all[0] != multi.BaseError || all[1] != retryable.BaseError || all[2] != validationErr {
+		t.Errorf("Unexpected errors in walk order: %v", all)
+	}
+
+	if fields := errors.All[errors.FieldError](validationErr); len(fields) != 0 {
+		t.Errorf("Expected no matches, got %v", fields)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	isValidation := func(err error) bool {
+		e, ok := err.(*errors.Error)
+		return ok && e.Category == errors.CategoryValidation
+	}
+
+	emailErr := errors.NewValidation("invalid email", errors.FieldError{Field: "email", Message: "required"})
+	authErr := errors.New("denied", errors.CategoryAuth)
+	multi := errors.WrapAll(errors.CategoryOperation, "batch failed", authErr, emailErr, fmt.Errorf("plain"))
+
+	filtered := errors.Filter(multi, isValidation)
+	filteredMulti, ok := filtered.(*errors.MultiError)
+	if !ok {
+		t.Fatalf("Expected a MultiError, got %T", filtered)
+	}
+	if len(filteredMulti.Causes) != 1 || filteredMulti.Causes[0] != emailErr {
+		t.Errorf("Expected only the validation branch, got %v", filteredMulti.Causes)
+	}
+	if len(multi.Causes) != 3 {
+		t.Error("Expected Filter to leave the original untouched")
+	}
+
+	wrapped := &errors.Error{
+		Category: errors.CategoryHandler,
+		Message:  "handler",
+		Source:   errors.Join(authErr, emailErr),
+	}
+	filteredWrap, ok := errors.Filter(wrapped, isValidation).(*errors.Error)
+	if !ok || filteredWrap == wrapped {
+		t.Fatalf("Expected a cloned rich error, got %v", filteredWrap)
+	}
+	if errors.HasCategory(filteredWrap, errors.CategoryAuth) || !errors.HasCategory(filteredWrap, errors.CategoryValidation) {
+		t.Errorf("Expected the auth branch to be pruned, got %v", errors.CategoriesOf(filteredWrap))
+	}
+
+	if got := errors.Filter(authErr, isValidation); got != nil {
+		t.Errorf("Expected nil without matches, got %v", got)
+	}
+	if got := errors.Filter(emailErr, isValidation); got != emailErr {
+		t.Errorf("Expected an unchanged error when nothing is pruned, got %v", got)
+	}
+}
+
+func TestFilter_RootMatch(t *testing.T) {
+	authErr := errors.New("denied", errors.CategoryAuth)
+	plainErr := fmt.Errorf("plain")
+
+	roots := map[string]error{
+		"multi": errors.WrapAll(errors.CategoryOperation, "batch failed", authErr, plainErr),
+		"join":  errors.Join(authErr, plainErr),
+	}
+	for name, root := range roots {
+		t.Run(name, func(t *testing.T) {
+			filtered := errors.Filter(root, func(err error) bool { return err == root })
+			if filtered != root {
+				t.Fatalf("Expected the matching root to be kept whole, got %v", filtered)
+			}
+			if len(errors.All[*errors.Error](filtered)) == 0 {
+				t.Error("Expected the root to keep its causes")
+			}
+		})
+	}
+}
+
+func TestTraversalHelpersOnJoinedErrors(t *testing.T) {
+	emailErr := errors.NewValidation("invalid", errors.FieldError{Field: "email", Message: "required"})
+	ozzoErr := validation.Errors{"name": validation.NewError("validation_required", "cannot be blank")}
+	joined := errors.Join(fmt.Errorf("first: %w", emailErr), ozzoErr)
+
+	fieldErrors, ok := errors.GetValidationErrors(joined)
+	if !ok || len(fieldErrors) != 2 {
+		t.Errorf("Expected validation errors from both branches, got %v", fieldErrors)
+	}
+
+	wrapped := &errors.Error{Category: errors.CategoryHandler, Message: "handler", Source: joined}
+	if all := wrapped.AllValidationErrors(); len(all) != 2 {
+		t.Errorf("Expected AllValidationErrors to see both branches, got %v", all)
+	}
+
+	if root := errors.RootCause(joined); root != emailErr {
+		t.Errorf("Expected RootCause to follow the first branch, got %v", root)
+	}
+}